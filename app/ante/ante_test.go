@@ -1,6 +1,7 @@
 package ante_test
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -13,11 +14,14 @@ import (
 	tmcrypto "github.com/tendermint/tendermint/crypto"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 
 	"github.com/cosmos/ethermint/app"
 	"github.com/cosmos/ethermint/app/ante"
+	"github.com/cosmos/ethermint/crypto/ethsecp256k1"
 	"github.com/cosmos/ethermint/types"
 	evmtypes "github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
 )
 
 func requireValidTx(
@@ -298,3 +302,99 @@ func (suite *AnteTestSuite) TestEthInvalidChainID() {
 	ctx := suite.ctx.WithChainID("bad-chain-id")
 	requireInvalidTx(suite.T(), suite.anteHandler, ctx, tx, false)
 }
+
+func (suite *AnteTestSuite) TestZKProofVerification() {
+	initialSN, initialCMT := ethcmn.BytesToHash([]byte("initial sn")), ethcmn.BytesToHash([]byte("initial cmt"))
+	zktx.SetInitialNote(initialSN, initialCMT)
+	suite.app.EvmKeeper.Verifier = app.AcceptVerifier{}
+	params := evmtypes.NewParams(types.AttoPhoton, 100000, 200000, 300000, 400000)
+	suite.app.EvmKeeper.SetParams(suite.ctx, params)
+	decorator := ante.NewZKProofVerificationDecorator(suite.app.AccountKeeper, suite.app.EvmKeeper)
+
+	addr1, priv1 := newTestAddrKey()
+	acc := suite.app.AccountKeeper.NewAccountWithAddress(suite.ctx, addr1)
+	_ = acc.SetCoins(newTestCoins())
+	suite.app.AccountKeeper.SetAccount(suite.ctx, acc)
+
+	newMint := func(malleate func(msg *evmtypes.MsgEthereumTx)) sdk.Tx {
+		cmt := ethcmn.BytesToHash([]byte("cmt"))
+		msg := evmtypes.NewMsgEthereumTx(0, &zktx.ZKTxAddress, big.NewInt(0), 300000, big.NewInt(0), nil)
+		msg.SetTxCode(evmtypes.MintTx)
+		msg.SetZKValue(10)
+		msg.SetZKSN(&initialSN)
+		msg.SetZKCMTOld(&initialCMT)
+		msg.SetZKCMT(&cmt)
+		msg.SetZKProof([]byte("proof"))
+		malleate(&msg)
+
+		// sign and cache the sender like the signature verification decorator
+		suite.Require().NoError(msg.Sign(big.NewInt(3), priv1.(ethsecp256k1.PrivKey).ToECDSA()))
+		_, err := msg.VerifySig(big.NewInt(3))
+		suite.Require().NoError(err)
+		return msg
+	}
+	next := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) { return ctx, nil }
+
+	testCases := []struct {
+		msg      string
+		malleate func(msg *evmtypes.MsgEthereumTx)
+		expErr   error
+	}{
+		{"valid mint", func(*evmtypes.MsgEthereumTx) {}, nil},
+		{"empty proof", func(msg *evmtypes.MsgEthereumTx) { msg.SetZKProof(nil) }, evmtypes.ErrMalformedShieldedTx},
+		{"wrong recipient", func(msg *evmtypes.MsgEthereumTx) {
+			to := ethcmn.BytesToAddress([]byte("other"))
+			msg.SetTxRecepient(&to)
+		}, evmtypes.ErrMalformedShieldedTx},
+		{"send without AUX", func(msg *evmtypes.MsgEthereumTx) {
+			msg.SetTxCode(evmtypes.SendTx)
+			msg.SetZKProof([]byte("proof"))
+		}, evmtypes.ErrMalformedShieldedTx},
+		{"unknown note", func(msg *evmtypes.MsgEthereumTx) {
+			cmt := ethcmn.BytesToHash([]byte("unknown"))
+			msg.SetZKCMTOld(&cmt)
+		}, evmtypes.ErrUnknownNote},
+		{"mint above balance", func(msg *evmtypes.MsgEthereumTx) { msg.SetZKValue(500000001) }, sdkerrors.ErrInsufficientFunds},
+	}
+
+	for _, tc := range testCases {
+		ctx := suite.ctx.WithGasMeter(sdk.NewGasMeter(300000))
+		_, err := decorator.AnteHandle(ctx, newMint(tc.malleate), false, next)
+		if tc.expErr != nil {
+			suite.Require().True(errors.Is(err, tc.expErr), "%s: %v", tc.msg, err)
+			suite.Require().Less(ctx.GasMeter().GasConsumed(), params.MintProofGas, tc.msg)
+		} else {
+			suite.Require().NoError(err, tc.msg)
+			suite.Require().GreaterOrEqual(ctx.GasMeter().GasConsumed(), params.MintProofGas, tc.msg)
+		}
+	}
+
+	// a second transaction spending the same SN is rejected at CheckTx, before
+	// its proof is verified
+	sn := ethcmn.BytesToHash([]byte("sn"))
+	spend := func(msg *evmtypes.MsgEthereumTx) { msg.SetZKSN(&sn) }
+	checkCtx := func(txBytes string) sdk.Context {
+		return suite.ctx.WithIsCheckTx(true).WithTxBytes([]byte(txBytes)).WithGasMeter(sdk.NewGasMeter(300000))
+	}
+	_, err := decorator.AnteHandle(checkCtx("tx1"), newMint(spend), false, next)
+	suite.Require().NoError(err)
+	ctx := checkCtx("tx2")
+	_, err = decorator.AnteHandle(ctx, newMint(spend), false, next)
+	suite.Require().True(errors.Is(err, evmtypes.ErrSNPending), err)
+	suite.Require().Less(ctx.GasMeter().GasConsumed(), params.MintProofGas)
+
+	// the initial SN is never pending
+	_, err = decorator.AnteHandle(checkCtx("tx3"), newMint(func(*evmtypes.MsgEthereumTx) {}), false, next)
+	suite.Require().NoError(err)
+	_, err = decorator.AnteHandle(checkCtx("tx4"), newMint(func(*evmtypes.MsgEthereumTx) {}), false, next)
+	suite.Require().NoError(err)
+
+	// and a processed SN is spent
+	suite.app.EvmKeeper.SetNullifier(suite.ctx, evmtypes.NewNullifier(sn, 1, ethcmn.Hash{}))
+	_, err = decorator.AnteHandle(suite.ctx.WithGasMeter(sdk.NewGasMeter(300000)), newMint(spend), false, next)
+	suite.Require().True(errors.Is(err, evmtypes.ErrSNSpent), err)
+
+	// the proof gas counts in the intrinsic gas checked at CheckTx
+	tx := newMint(func(msg *evmtypes.MsgEthereumTx) { msg.Data.GasLimit = 50000 })
+	requireInvalidTx(suite.T(), ante.NewAnteHandler(suite.app.AccountKeeper, suite.app.EvmKeeper, suite.app.SupplyKeeper), suite.ctx.WithIsCheckTx(true), tx, false)
+}
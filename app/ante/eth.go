@@ -1,12 +1,7 @@
 package ante
 
 import (
-	"crypto/ecdsa"
-	"errors"
 	"fmt"
-	"github.com/cosmos/ethermint/x/evm"
-	"github.com/cosmos/ethermint/zktx"
-	"github.com/ethereum/go-ethereum/crypto"
 	"math/big"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -17,6 +12,7 @@ import (
 
 	ethermint "github.com/cosmos/ethermint/types"
 	evmtypes "github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
 
 	"github.com/ethereum/go-ethereum/common"
 	ethcore "github.com/ethereum/go-ethereum/core"
@@ -25,6 +21,11 @@ import (
 // EVMKeeper defines the expected keeper interface used on the Eth AnteHandler
 type EVMKeeper interface {
 	GetParams(ctx sdk.Context) evmtypes.Params
+	HasNote(ctx sdk.Context, owner common.Address, cmt common.Hash) bool
+	HasCommitmentRoot(ctx sdk.Context, root common.Hash) bool
+	CheckSN(ctx sdk.Context, sn common.Hash) error
+	AddPendingSN(ctx sdk.Context, sn common.Hash)
+	VerifyProof(ctx sdk.Context, msg evmtypes.MsgEthereumTx, simulate bool) error
 }
 
 // EthSetupContextDecorator sets the infinite GasMeter in the Context and wraps
@@ -327,6 +328,8 @@ func (egcd EthGasConsumeDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simula
 	if err != nil {
 		return ctx, sdkerrors.Wrap(err, "failed to compute intrinsic gas cost")
 	}
+	// shielded transactions also pay for the verification of their proof
+	gas += egcd.evmKeeper.GetParams(ctx).ProofGas(msgEthTx.Code())
 
 	// intrinsic gas verification during CheckTx
 	if ctx.IsCheckTx() && gasLimit < gas {
@@ -352,50 +355,124 @@ func (egcd EthGasConsumeDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simula
 
 	// Set gas meter after ante handler to ignore gaskv costs
 	newCtx = auth.SetGasMeter(simulate, ctx, gasLimit)
+	return next(newCtx, tx, simulate)
+}
 
+// ZKProofVerificationDecorator checks the zk fields of shielded transactions and
+// verifies their proof, charging the verification gas of their circuit.
+//
+// CONTRACT: must be called after the EthGasConsumeDecorator, which sets the gas
+// meter of the transaction.
+type ZKProofVerificationDecorator struct {
+	ak        auth.AccountKeeper
+	evmKeeper EVMKeeper
+}
+
+// NewZKProofVerificationDecorator creates a new ZKProofVerificationDecorator
+func NewZKProofVerificationDecorator(ak auth.AccountKeeper, ek EVMKeeper) ZKProofVerificationDecorator {
+	return ZKProofVerificationDecorator{
+		ak:        ak,
+		evmKeeper: ek,
+	}
+}
 
+// AnteHandle rejects shielded transactions with malformed zk fields, spending a
+// note their sender doesn't hold or whose SN is spent or pending, or proven
+// against an unknown commitment root, before charging the proof verification gas
+// from the evm params and verifying the proof. The SN of a transaction accepted
+// at CheckTx is then pending until it is processed. Public transactions are
+// passed through.
+func (zpvd ZKProofVerificationDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (newCtx sdk.Context, err error) {
+	msgEthTx, ok := tx.(evmtypes.MsgEthereumTx)
+	if !ok {
+		return ctx, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "invalid transaction type: %T", tx)
+	}
 
+	if msgEthTx.Shielded == nil {
+		return next(ctx, tx, simulate)
+	}
 
-	//verProofStart := time.Now()
-	txCode := msgEthTx.Code()
-	addr := common.BytesToAddress(address)
-	//fmt.Println(addr)
-	keeper := egcd.evmKeeper.(evm.Keeper)
-	if txCode == evmtypes.MintTx {
-		balance := keeper.GetBalance(ctx,addr)
-		fmt.Println(balance)
-		if balance.Uint64() < msgEthTx.ZKValue() {
-			return ctx,errors.New("not enough balance")
-		}
-		cmtbalance := keeper.GetCMTBalance(addr)
-		err = zktx.VerifyMintProof(&cmtbalance, msgEthTx.ZKSN(), msgEthTx.ZKCMT(), msgEthTx.ZKValue(), msgEthTx.ZKProof()) //TBD
-		if err != nil {
-			return ctx,err
-		}
+	if err := validateShieldedTx(msgEthTx); err != nil {
+		return ctx, err
 	}
-	if txCode == evmtypes.RedeemTx {
-		cmtbalance := keeper.GetCMTBalance(addr)
-		err = zktx.VerifyRedeemProof(&cmtbalance, msgEthTx.ZKSN(), msgEthTx.ZKCMT(), msgEthTx.ZKValue(), msgEthTx.ZKProof()) //TBD
-		if err != nil {
-			return ctx,err
+
+	// every account starts from the same initial note, so its SN is never nullified
+	initialSN, _, err := zktx.InitialNote()
+	if err != nil {
+		return ctx, err
+	}
+
+	// sender address should be in the tx cache from the previous AnteHandle call
+	address := msgEthTx.From()
+	sender := common.BytesToAddress(address)
+	if !zpvd.evmKeeper.HasNote(ctx, sender, *msgEthTx.ZKCMTOld()) {
+		return ctx, sdkerrors.Wrapf(evmtypes.ErrUnknownNote, "sender %s", sender.Hex())
+	}
+
+	sn := *msgEthTx.ZKSN()
+	spendsSN := sn != initialSN
+	if spendsSN {
+		if err := zpvd.evmKeeper.CheckSN(ctx, sn); err != nil {
+			return ctx, err
 		}
 	}
-	if txCode == evmtypes.SendTx {
-		cmtbalance := keeper.GetCMTBalance(addr)
-		err = zktx.VerifySendProof(msgEthTx.ZKSN(), msgEthTx.ZKCMTS(), msgEthTx.ZKProof(), &cmtbalance, msgEthTx.ZKCMT()) //TBD
-		if err != nil {
-			return ctx,err
+
+	params := zpvd.evmKeeper.GetParams(ctx)
+	switch data := msgEthTx.Shielded.(type) {
+	case *evmtypes.MintTxData:
+		// the minted value is debited from the public balance left after the fees
+		acc := zpvd.ak.GetAccount(ctx, address)
+		balance := acc.GetCoins().AmountOf(params.EvmDenom)
+		if balance.BigInt().Cmp(new(big.Int).SetUint64(data.Value)) < 0 {
+			return ctx, sdkerrors.Wrapf(
+				sdkerrors.ErrInsufficientFunds,
+				"sender balance < minted value (%s < %d%s)", balance, data.Value, params.EvmDenom,
+			)
+		}
+	case *evmtypes.DepositTxData:
+		if !zpvd.evmKeeper.HasCommitmentRoot(ctx, data.RTcmt) {
+			return ctx, sdkerrors.Wrapf(evmtypes.ErrUnknownCommitmentRoot, "root %s", data.RTcmt.Hex())
 		}
 	}
-	if txCode == evmtypes.DepositTx {
-		cmtbalance := keeper.GetCMTBalance(addr)
-		ppp := &ecdsa.PublicKey{crypto.S256(), msgEthTx.X(), msgEthTx.Y()}
-		err = zktx.VerifyDepositProof(ppp, msgEthTx.RTcmt(), &cmtbalance, msgEthTx.ZKSN(), msgEthTx.ZKCMT(), msgEthTx.ZKSNS(), msgEthTx.ZKProof())
-		if err != nil {
-			return ctx, err
+
+	// the gas is charged whether or not the verification result is cached, so
+	// that every node consumes the same gas
+	ctx.GasMeter().ConsumeGas(params.ProofGas(msgEthTx.Code()), "ante verify: zk proof")
+	if err := zpvd.evmKeeper.VerifyProof(ctx, msgEthTx, simulate); err != nil {
+		return ctx, err
+	}
+
+	newCtx, err = next(ctx, tx, simulate)
+	if err == nil && spendsSN && ctx.IsCheckTx() && !simulate {
+		zpvd.evmKeeper.AddPendingSN(ctx, sn)
+	}
+	return newCtx, err
+}
+
+// validateShieldedTx checks that a shielded transaction is sent to the shielded
+// pool address and carries the zk fields of its type.
+func validateShieldedTx(msg evmtypes.MsgEthereumTx) error {
+	if msg.To() == nil || *msg.To() != zktx.ZKTxAddress {
+		return sdkerrors.Wrapf(evmtypes.ErrMalformedShieldedTx, "recipient must be %s", zktx.ZKTxAddress.Hex())
+	}
+	if len(msg.ZKProof()) == 0 {
+		return sdkerrors.Wrap(evmtypes.ErrMalformedShieldedTx, "empty proof")
+	}
+
+	switch data := msg.Shielded.(type) {
+	case *evmtypes.MintTxData, *evmtypes.RedeemTxData:
+	case *evmtypes.SendTxData:
+		if len(data.AUX) == 0 || data.X == nil || data.Y == nil {
+			return sdkerrors.Wrap(evmtypes.ErrMalformedShieldedTx, "missing AUX or one-time key")
+		}
+	case *evmtypes.DepositTxData:
+		if data.X == nil || data.Y == nil {
+			return sdkerrors.Wrap(evmtypes.ErrMalformedShieldedTx, "missing one-time key")
 		}
+	default:
+		return sdkerrors.Wrapf(evmtypes.ErrMalformedShieldedTx, "unsupported transaction type %T", data)
 	}
-	return next(newCtx, tx, simulate)
+	return nil
 }
 
 // IncrementSenderSequenceDecorator increments the sequence of the signers. The
@@ -30,6 +30,7 @@ import (
 	ethermint "github.com/cosmos/ethermint/types"
 	"github.com/cosmos/ethermint/x/evm"
 	"github.com/cosmos/ethermint/x/faucet"
+	"github.com/cosmos/ethermint/zktx"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
@@ -46,6 +47,10 @@ func init() {
 
 const appName = "Ethermint"
 
+// ProofGasUpgrade is the name of the software upgrade that sets the proof
+// verification gas parameters of the evm module on chains started without them.
+const ProofGasUpgrade = "proof-gas"
+
 var (
 	// DefaultCLIHome sets the default home directories for the application CLI
 	DefaultCLIHome = os.ExpandEnv("$HOME/.ethermintcli")
@@ -85,6 +90,7 @@ var (
 		staking.NotBondedPoolName: {supply.Burner, supply.Staking},
 		gov.ModuleName:            {supply.Burner},
 		faucet.ModuleName:         {supply.Minter},
+		evm.ModuleName:            nil,
 	}
 
 	// module accounts that are allowed to receive tokens
@@ -142,6 +148,7 @@ func NewEthermintApp(
 	loadLatest bool,
 	skipUpgradeHeights map[int64]bool,
 	invCheckPeriod uint,
+	verifier zktx.Verifier,
 	baseAppOptions ...func(*bam.BaseApp),
 ) *EthermintApp {
 
@@ -212,8 +219,11 @@ func NewEthermintApp(
 	)
 	app.UpgradeKeeper = upgrade.NewKeeper(skipUpgradeHeights, keys[upgrade.StoreKey], app.cdc)
 	app.EvmKeeper = evm.NewKeeper(
-		app.cdc, keys[evm.StoreKey], app.subspaces[evm.ModuleName], app.AccountKeeper,
+		app.cdc, keys[evm.StoreKey], app.subspaces[evm.ModuleName], app.AccountKeeper, verifier,
 	)
+	app.UpgradeKeeper.SetUpgradeHandler(ProofGasUpgrade, func(ctx sdk.Context, _ upgrade.Plan) {
+		app.EvmKeeper.SetMissingParams(ctx)
+	})
 	app.FaucetKeeper = faucet.NewKeeper(
 		app.cdc, keys[faucet.StoreKey], app.SupplyKeeper,
 	)
@@ -259,7 +269,7 @@ func NewEthermintApp(
 		distr.NewAppModule(app.DistrKeeper, app.AccountKeeper, app.SupplyKeeper, app.StakingKeeper),
 		staking.NewAppModule(app.StakingKeeper, app.AccountKeeper, app.SupplyKeeper),
 		evidence.NewAppModule(app.EvidenceKeeper),
-		evm.NewAppModule(app.EvmKeeper, app.AccountKeeper),
+		evm.NewAppModule(app.EvmKeeper, app.AccountKeeper, app.SupplyKeeper),
 		faucet.NewAppModule(app.FaucetKeeper),
 	)
 
@@ -276,10 +286,12 @@ func NewEthermintApp(
 
 	// NOTE: The genutils module must occur after staking so that pools are
 	// properly initialized with tokens from genesis accounts.
+	// NOTE: The evm module must occur before crisis so that its params are set
+	// when the invariants are asserted.
 	app.mm.SetOrderInitGenesis(
 		auth.ModuleName, distr.ModuleName, staking.ModuleName, bank.ModuleName,
 		slashing.ModuleName, gov.ModuleName, mint.ModuleName, supply.ModuleName,
-		crisis.ModuleName, genutil.ModuleName, evidence.ModuleName, evm.ModuleName,
+		evm.ModuleName, crisis.ModuleName, genutil.ModuleName, evidence.ModuleName,
 		faucet.ModuleName,
 	)
 
@@ -1,17 +1,23 @@
 package app
 
 import (
+	"crypto/ecdsa"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
 	dbm "github.com/tendermint/tm-db"
 
 	"github.com/cosmos/cosmos-sdk/codec"
+
+	"github.com/cosmos/ethermint/zktx"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // Setup initializes a new EthermintApp. A Nop logger is set in EthermintApp.
 func Setup(isCheckTx bool) *EthermintApp {
 	db := dbm.NewMemDB()
-	app := NewEthermintApp(log.NewNopLogger(), db, nil, true, map[int64]bool{}, 0)
+	app := NewEthermintApp(log.NewNopLogger(), db, nil, true, map[int64]bool{}, 0, zktx.DefaultVerifier())
 
 	if !isCheckTx {
 		// init chain must be called to stop deliverState from being nil
@@ -32,3 +38,29 @@ func Setup(isCheckTx bool) *EthermintApp {
 
 	return app
 }
+
+// AcceptVerifier is a zktx.Verifier accepting every proof. It is used by tests
+// that build shielded transactions without a prover.
+type AcceptVerifier struct{}
+
+var _ zktx.Verifier = AcceptVerifier{}
+
+// VerifyMintProof implements zktx.Verifier.
+func (AcceptVerifier) VerifyMintProof(*common.Hash, *common.Hash, *common.Hash, uint64, []byte) error {
+	return nil
+}
+
+// VerifySendProof implements zktx.Verifier.
+func (AcceptVerifier) VerifySendProof(*common.Hash, *common.Hash, []byte, *common.Hash, *common.Hash) error {
+	return nil
+}
+
+// VerifyDepositProof implements zktx.Verifier.
+func (AcceptVerifier) VerifyDepositProof(*ecdsa.PublicKey, common.Hash, *common.Hash, *common.Hash, *common.Hash, *common.Hash, []byte) error {
+	return nil
+}
+
+// VerifyRedeemProof implements zktx.Verifier.
+func (AcceptVerifier) VerifyRedeemProof(*common.Hash, *common.Hash, *common.Hash, uint64, []byte) error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/ethermint/zktx"
+)
+
+const (
+	flagProverListenAddr = "laddr"
+	flagProverTokenFile  = "token-file"
+	flagProverTLSCert    = "tls-cert"
+	flagProverTLSKey     = "tls-key"
+)
+
+// ProverCmd creates a CLI command to run a prover worker that generates the
+// zk-SNARK proofs of shielded transactions on behalf of the RPC servers.
+func ProverCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prover",
+		Short: "Start a zk-SNARK prover worker",
+		Long: `Start a worker that generates the zk-SNARK proofs of shielded transactions over HTTP.
+REST/RPC servers started with --prover=<address> delegate proof generation to it.
+
+The proof requests carry the spending keys of the notes, so they are authenticated
+with the token of --token-file, shared with the RPC servers, and served over TLS
+with --tls-cert and --tls-key. Without TLS the worker only listens on a loopback
+address.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !zktx.LibsnarkEnabled {
+				return zktx.ErrLibsnarkDisabled
+			}
+
+			laddr, err := cmd.Flags().GetString(flagProverListenAddr)
+			if err != nil {
+				return err
+			}
+			tokenFile, err := cmd.Flags().GetString(flagProverTokenFile)
+			if err != nil {
+				return err
+			}
+			certFile, err := cmd.Flags().GetString(flagProverTLSCert)
+			if err != nil {
+				return err
+			}
+			keyFile, err := cmd.Flags().GetString(flagProverTLSKey)
+			if err != nil {
+				return err
+			}
+
+			if tokenFile == "" {
+				return fmt.Errorf("--%s is required", flagProverTokenFile)
+			}
+			token, err := zktx.LoadProverToken(tokenFile)
+			if err != nil {
+				return err
+			}
+
+			addr := strings.TrimPrefix(laddr, "tcp://")
+			useTLS := certFile != "" || keyFile != ""
+			if !useTLS {
+				host, _, err := net.SplitHostPort(addr)
+				if err != nil {
+					return err
+				}
+				if !zktx.IsLoopbackHost(host) {
+					return fmt.Errorf("%w: set --%s and --%s to listen on %s", zktx.ErrInsecureProver, flagProverTLSCert, flagProverTLSKey, addr)
+				}
+			}
+
+			server := &http.Server{
+				Addr:              addr,
+				Handler:           zktx.NewProverHandler(zktx.LocalProver{}, token),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			fmt.Printf("prover listening on %s\n", addr)
+			if useTLS {
+				return server.ListenAndServeTLS(certFile, keyFile)
+			}
+			return server.ListenAndServe()
+		},
+	}
+
+	cmd.Flags().String(flagProverListenAddr, "tcp://localhost:8547", "The address for the prover worker to listen on")
+	cmd.Flags().String(flagProverTokenFile, "", "File holding the token authenticating the RPC servers")
+	cmd.Flags().String(flagProverTLSCert, "", "TLS certificate of the prover worker, required unless it listens on a loopback address")
+	cmd.Flags().String(flagProverTLSKey, "", "TLS private key of the prover worker")
+	return cmd
+}
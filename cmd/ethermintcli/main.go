@@ -2,12 +2,9 @@ package main
 
 import (
 	"fmt"
-	"github.com/cosmos/ethermint/zktx"
 	"github.com/spf13/cobra"
 	tmamino "github.com/tendermint/tendermint/crypto/encoding/amino"
 	"github.com/tendermint/tendermint/libs/cli"
-	"os"
-	"path/filepath"
 
 	sdkclient "github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
@@ -70,6 +67,7 @@ func main() {
 		client.ValidateChainID(
 			rpc.ServeCmd(cdc),
 		),
+		client.ProverCmd(),
 		flags.LineBreak,
 		client.KeyCommands(),
 		flags.LineBreak,
@@ -139,45 +137,7 @@ func txCmd(cdc *sdkcodec.Codec) *cobra.Command {
 	}
 
 	txCmd.RemoveCommand(cmdsToRemove...)
-	setFNfile()
 
 	return txCmd
 }
 
-func setFNfile()  {
-	DBdir  := os.ExpandEnv("$HOME/.ethermintcli")
-	//DBdir, _ := filepath.Abs("$HOME/.ethermintcli")
-	SNFilePath := filepath.Join(DBdir, "SN")
-	SNfile, errOpenFile := os.OpenFile(SNFilePath, os.O_RDWR|os.O_CREATE, 0600)
-	if errOpenFile != nil {
-		fmt.Println("OpenFile error: ", errOpenFile)
-	}
-	zktx.SNfile = SNfile
-	////rd := bufio.NewReader(zktx.SNfile)
-	////SSNBytesString2, errReading := rd.ReadString('\n')
-	////
-	////if errReading != nil {
-	////	fmt.Println("Readiong string error: ", errReading)
-	////}
-	//var SSNBytesString2 string
-	//var SNS zktx.SequenceS
-	//if len(SSNBytesString2) != 0 {
-	//	SSNBytesString := SSNBytesString2[0 : len(SSNBytesString2)-1]
-	//	SNSbytes, errDecodeString := hex.DecodeString(SSNBytesString)
-	//	if errDecodeString != nil {
-	//		fmt.Println("Decode string  error: ", errDecodeString)
-	//	}
-	//	errDecodeBytes := rlp.DecodeBytes(SNSbytes, &SNS)
-	//	if errDecodeBytes != nil {
-	//		fmt.Println("Decode SNSbytes error: ", errDecodeBytes)
-	//	}
-	//	zktx.SequenceNumber = &SNS.Suquence1
-	//	zktx.SequenceNumberAfter = &SNS.Suquence2
-	//	zktx.SNS = SNS.SNS
-	//	zktx.RandomReceiverPK = &ecdsa.PublicKey{crypto.S256(), SNS.PKBX, SNS.PKBY}
-	//	zktx.Stage = SNS.Stage
-	//}
-	//zktx.SNfile.Close()
-	//fmt.Println(zktx.SNfile)
-}
-
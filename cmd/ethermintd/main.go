@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -11,6 +12,7 @@ import (
 	tmtypes "github.com/tendermint/tendermint/types"
 	dbm "github.com/tendermint/tm-db"
 	"io"
+	"path/filepath"
 
 	"github.com/cosmos/cosmos-sdk/baseapp"
 	"github.com/cosmos/cosmos-sdk/client/flags"
@@ -30,9 +32,14 @@ import (
 	"github.com/cosmos/ethermint/codec"
 	"github.com/cosmos/ethermint/crypto/ethsecp256k1"
 	ethermint "github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/zktx"
 )
 
-const flagInvCheckPeriod = "inv-check-period"
+const (
+	flagInvCheckPeriod = "inv-check-period"
+	flagZKVerifier     = "zk-verifier"
+	flagZKVKDir        = "zk-vk-dir"
+)
 
 var invCheckPeriod uint
 
@@ -86,6 +93,13 @@ func main() {
 	executor := cli.PrepareBaseCmd(rootCmd, "EM", app.DefaultNodeHome)
 	rootCmd.PersistentFlags().UintVar(&invCheckPeriod, flagInvCheckPeriod,
 		0, "Assert registered invariants every N blocks")
+	rootCmd.PersistentFlags().String(flagZKVerifier, zktx.VerifierLibsnark,
+		fmt.Sprintf("zk-SNARK proof verifier, either %s or the experimental %s, unverified against the libsnark circuits", zktx.VerifierLibsnark, zktx.VerifierGroth16))
+	rootCmd.PersistentFlags().String(flagZKVKDir, "",
+		"Directory holding the Groth16 verifying keys (default \"<home>/config/zk\")")
+	if err := viper.BindPFlags(rootCmd.PersistentFlags()); err != nil {
+		panic(err)
+	}
 	err := executor.Execute()
 	if err != nil {
 		panic(err)
@@ -100,6 +114,7 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer) abci.Application
 		true,
 		map[int64]bool{},
 		0,
+		newVerifier(),
 		baseapp.SetPruning(storetypes.NewPruningOptionsFromString(viper.GetString("pruning"))),
 		baseapp.SetMinGasPrices(viper.GetString(server.FlagMinGasPrices)),
 		baseapp.SetHaltHeight(uint64(viper.GetInt(server.FlagHaltHeight))),
@@ -110,7 +125,7 @@ func exportAppStateAndTMValidators(
 	logger log.Logger, db dbm.DB, traceStore io.Writer, height int64, forZeroHeight bool, jailWhiteList []string,
 ) (json.RawMessage, []tmtypes.GenesisValidator, error) {
 
-	ethermintApp := app.NewEthermintApp(logger, db, traceStore, true, map[int64]bool{}, 0)
+	ethermintApp := app.NewEthermintApp(logger, db, traceStore, true, map[int64]bool{}, 0, zktx.DefaultVerifier())
 
 	if height != -1 {
 		err := ethermintApp.LoadHeight(height)
@@ -121,3 +136,17 @@ func exportAppStateAndTMValidators(
 
 	return ethermintApp.ExportAppStateAndValidators(forZeroHeight, jailWhiteList)
 }
+
+// newVerifier creates the zk-SNARK proof verifier selected by the node flags.
+func newVerifier() zktx.Verifier {
+	vkDir := viper.GetString(flagZKVKDir)
+	if vkDir == "" {
+		vkDir = filepath.Join(viper.GetString(cli.HomeFlag), "config", "zk")
+	}
+
+	verifier, err := zktx.NewVerifier(viper.GetString(flagZKVerifier), vkDir)
+	if err != nil {
+		panic(err)
+	}
+	return verifier
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/cosmos/ethermint/types"
 	"github.com/cosmos/ethermint/x/evm"
 	evmtypes "github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
@@ -195,7 +196,7 @@ func TestImportBlocks(t *testing.T) {
 	authSubspace := paramsKeeper.Subspace(auth.DefaultParamspace)
 	evmSubspace := paramsKeeper.Subspace(evmtypes.DefaultParamspace).WithKeyTable(evmtypes.ParamKeyTable())
 	ak := auth.NewAccountKeeper(cdc, authStoreKey, authSubspace, types.ProtoAccount)
-	evmKeeper := evm.NewKeeper(cdc, evmStoreKey, evmSubspace, ak)
+	evmKeeper := evm.NewKeeper(cdc, evmStoreKey, evmSubspace, ak, zktx.DefaultVerifier())
 
 	cms.SetPruning(sdkstore.PruneNothing)
 
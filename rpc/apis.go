@@ -12,6 +12,7 @@ import (
 	"github.com/cosmos/ethermint/rpc/namespaces/personal"
 	"github.com/cosmos/ethermint/rpc/namespaces/web3"
 	rpctypes "github.com/cosmos/ethermint/rpc/types"
+	"github.com/cosmos/ethermint/zktx"
 )
 
 // RPC namespaces and API version
@@ -25,10 +26,10 @@ const (
 )
 
 // GetAPIs returns the list of all APIs from the Ethereum namespaces
-func GetAPIs(clientCtx context.CLIContext, keys ...ethsecp256k1.PrivKey) []rpc.API {
+func GetAPIs(clientCtx context.CLIContext, prover zktx.Prover, notes *zktx.NoteStore, scanner *eth.NoteScanner, keys ...ethsecp256k1.PrivKey) []rpc.API {
 	nonceLock := new(rpctypes.AddrLocker)
 	backend := backend.New(clientCtx)
-	ethAPI := eth.NewAPI(clientCtx, backend, nonceLock, keys...)
+	ethAPI := eth.NewAPI(clientCtx, backend, nonceLock, prover, notes, scanner, keys...)
 
 	return []rpc.API{
 		{
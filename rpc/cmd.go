@@ -13,6 +13,9 @@ func ServeCmd(cdc *codec.Codec) *cobra.Command {
 	cmd := lcd.ServeCommand(cdc, RegisterRoutes)
 	cmd.Flags().String(flagUnlockKey, "", "Select a key to unlock on the RPC server")
 	cmd.Flags().String(flagWebsocket, "8546", "websocket port to listen to")
+	cmd.Flags().String(flagProver, "", "Address of the prover worker generating zk-SNARK proofs, https unless on a loopback address (default: generate them in-process)")
+	cmd.Flags().String(flagProverToken, "", "File holding the token authenticating the RPC server to the prover worker")
+	cmd.Flags().String(flagProverCA, "", "PEM certificates trusted to sign the TLS certificate of the prover worker (default: the system roots)")
 	cmd.Flags().StringP(flags.FlagBroadcastMode, "b", flags.BroadcastSync, "Transaction broadcasting mode (sync|async|block)")
 	return cmd
 }
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"bufio"
+	"crypto/tls"
 	"fmt"
 	"os"
 	"strings"
@@ -19,13 +20,26 @@ import (
 	"github.com/cosmos/ethermint/app"
 	"github.com/cosmos/ethermint/crypto/ethsecp256k1"
 	"github.com/cosmos/ethermint/crypto/hd"
+	"github.com/cosmos/ethermint/rpc/namespaces/eth"
 	"github.com/cosmos/ethermint/rpc/websockets"
+	"github.com/cosmos/ethermint/zktx"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rpc"
+
+	dbm "github.com/tendermint/tm-db"
 )
 
 const (
-	flagUnlockKey = "unlock-key"
-	flagWebsocket = "wsport"
+	flagUnlockKey   = "unlock-key"
+	flagWebsocket   = "wsport"
+	flagProver      = "prover"
+	flagProverToken = "prover-token-file"
+	flagProverCA    = "prover-ca"
+
+	// walletDBName is the name of the database holding the shielded notes in the
+	// home directory
+	walletDBName = "wallet"
 )
 
 // RegisterRoutes creates a new server and registers the `/rpc` endpoint.
@@ -60,7 +74,22 @@ func RegisterRoutes(rs *lcd.RestServer) {
 		}
 	}
 
-	apis := GetAPIs(rs.CliCtx, privkeys...)
+	// reload the shielded notes of the unlocked accounts from the wallet
+	walletDB := dbm.NewDB(walletDBName, dbm.GoLevelDBBackend, viper.GetString(flags.FlagHome))
+	notes := zktx.NewNoteStore(walletDB)
+	for _, key := range privkeys {
+		address := common.BytesToAddress(key.PubKey().Address().Bytes())
+		if err := notes.Open(address, zktx.DeriveWalletKey(key)); err != nil {
+			panic(fmt.Errorf("failed to load shielded wallet of %s: %w", address.Hex(), err))
+		}
+	}
+
+	prover, err := newProver()
+	if err != nil {
+		panic(fmt.Errorf("failed to configure the prover: %w", err))
+	}
+	scanner := eth.NewNoteScanner(rs.CliCtx, notes, privkeys...)
+	apis := GetAPIs(rs.CliCtx, prover, notes, scanner, privkeys...)
 
 	// Register all the APIs exposed by the namespace services
 	// TODO: handle allowlist and private APIs
@@ -70,6 +99,9 @@ func RegisterRoutes(rs *lcd.RestServer) {
 		}
 	}
 
+	// resync the shielded wallets and record the notes sent to the unlocked accounts
+	scanner.Start()
+
 	// Web3 RPC API route
 	rs.Mux.HandleFunc("/", server.ServeHTTP).Methods("POST", "OPTIONS")
 
@@ -84,6 +116,32 @@ func RegisterRoutes(rs *lcd.RestServer) {
 	ws.Start()
 }
 
+// newProver returns the prover worker set by the prover flags, or the in-process
+// prover if none is set.
+func newProver() (zktx.Prover, error) {
+	endpoint := viper.GetString(flagProver)
+	if endpoint == "" {
+		return zktx.LocalProver{}, nil
+	}
+
+	tokenFile := viper.GetString(flagProverToken)
+	if tokenFile == "" {
+		return nil, fmt.Errorf("--%s is required with --%s", flagProverToken, flagProver)
+	}
+	token, err := zktx.LoadProverToken(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var tlsConfig *tls.Config
+	if caFile := viper.GetString(flagProverCA); caFile != "" {
+		if tlsConfig, err = zktx.LoadProverCA(caFile); err != nil {
+			return nil, err
+		}
+	}
+	return zktx.NewProver(endpoint, token, tlsConfig)
+}
+
 func unlockKeyFromNameAndPassphrase(accountNames []string, passphrase string) ([]ethsecp256k1.PrivKey, error) {
 	keybase, err := keys.NewKeyring(
 		sdk.KeyringServiceName(),
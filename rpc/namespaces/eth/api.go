@@ -1,11 +1,10 @@
 package eth
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"crypto/ecdsa"
-	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/cosmos/ethermint/zktx"
@@ -14,6 +13,7 @@ import (
 	"math/big"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 
@@ -48,6 +48,10 @@ import (
 )
 
 const PubKeySize = 64
+
+// mergeTimeout is how long MergeNotes waits for the send transaction of the
+// merge to be committed.
+const mergeTimeout = time.Minute
 // PublicEthereumAPI is the eth_ prefixed set of APIs in the Web3 JSON-RPC spec.
 type PublicEthereumAPI struct {
 	ctx          context.Context
@@ -58,13 +62,15 @@ type PublicEthereumAPI struct {
 	keys         []ethsecp256k1.PrivKey // unlocked keys
 	nonceLock    *rpctypes.AddrLocker
 	keyringLock  sync.Mutex
-
+	prover       zktx.Prover
+	notes        *zktx.NoteStore
+	scanner      *NoteScanner
 }
 
 // NewAPI creates an instance of the public ETH Web3 API.
 func NewAPI(
 	clientCtx clientcontext.CLIContext, backend backend.Backend, nonceLock *rpctypes.AddrLocker,
-	keys ...ethsecp256k1.PrivKey,
+	prover zktx.Prover, notes *zktx.NoteStore, scanner *NoteScanner, keys ...ethsecp256k1.PrivKey,
 ) *PublicEthereumAPI {
 
 	epoch, err := ethermint.ParseChainID(clientCtx.ChainID)
@@ -80,6 +86,9 @@ func NewAPI(
 		backend:      backend,
 		keys:         keys,
 		nonceLock:    nonceLock,
+		prover:       prover,
+		notes:        notes,
+		scanner:      scanner,
 	}
 
 	if err := api.GetKeyringInfo(); err != nil {
@@ -425,8 +434,8 @@ func (api *PublicEthereumAPI) SendRawTransaction(data hexutil.Bytes) (common.Has
 	api.logger.Debug("eth_sendRawTransaction", "data", data)
 	tx := new(evmtypes.MsgEthereumTx)
 
-	// RLP decode raw transaction bytes
-	if err := rlp.DecodeBytes(data, tx); err != nil {
+	// decode the legacy RLP or typed envelope bytes of the raw transaction
+	if err := tx.UnmarshalBinary(data); err != nil {
 		// Return nil is for when gasLimit overflows uint64
 		return common.Hash{}, nil
 	}
@@ -899,6 +908,30 @@ func (api *PublicEthereumAPI) generateFromArgs(args rpctypes.SendTxArgs) (*evmty
 }
 
 
+// generateShieldedFromArgs assembles a shielded transaction with the given code
+// from the args. Unless args.Gas is set, the gas limit also covers the
+// verification of its proof, which the estimated gas doesn't include.
+func (api *PublicEthereumAPI) generateShieldedFromArgs(args rpctypes.SendTxArgs, code uint8) (*evmtypes.MsgEthereumTx, error) {
+	tx, err := api.generateFromArgs(args)
+	if err != nil || args.Gas != nil {
+		return tx, err
+	}
+
+	res, _, err := api.clientCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", evmtypes.ModuleName, evmtypes.QueryParameters), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var params evmtypes.Params
+	if err := api.clientCtx.Codec.UnmarshalJSON(res, &params); err != nil {
+		return nil, err
+	}
+
+	tx.Data.GasLimit += params.ProofGas(code)
+	return tx, nil
+}
+
+
 
 // SendPublicTransaction creates a transaction for the given argument, sign it and submit it to the
 // transaction pool.
@@ -1012,31 +1045,178 @@ func (api *PublicEthereumAPI) countRedeemTransaction(block *tmtypes.Block) int{
 	return cnt
 }
 
-// GetTransactionCount returns the number of transactions at the given address up to the given block number.
+// GetSN returns whether the given serial number has already been spent.
 func (api *PublicEthereumAPI) GetSN(SN *common.Hash) (bool, error) {
-	res, _, err := api.clientCtx.QueryWithData(fmt.Sprintf("custom/%s/%s/%s", evmtypes.ModuleName, evmtypes.QueryResSN, SN.Bytes()), nil)
+	res, _, err := api.clientCtx.QueryWithData(fmt.Sprintf("custom/%s/%s/%s", evmtypes.ModuleName, evmtypes.QueryResSN, SN.Hex()), nil)
 	if err != nil {
 		return false, err
 	}
 
-	out := new(evmtypes.QuerySN)
-	if err := api.clientCtx.Codec.UnmarshalJSON(res, &out); err != nil {
+	var out evmtypes.QuerySN
+	if err := json.Unmarshal(res, &out); err != nil {
 		return false, err
 	}
-	if out ==nil{
-		return false, err
+	return out.Spent, nil
+}
+
+// GetShieldedPublicKey returns the incoming viewing public key of an unlocked
+// account, which senders pass as the pubKey of eth_sendSendTransaction.
+func (api *PublicEthereumAPI) GetShieldedPublicKey(address common.Address) (hexutil.Bytes, error) {
+	api.logger.Debug("eth_getShieldedPublicKey", "address", address)
+	key, exist := rpctypes.GetKeyByAddress(api.keys, address)
+	if !exist {
+		return nil, keystore.ErrLocked
 	}
-	return true, nil
+
+	return zktx.NewShieldedKeys(key.ToECDSA()).IncomingViewingPubKey()
 }
 
-func (api *PublicEthereumAPI) SendMintTransaction(args rpctypes.SendTxArgs) (common.Hash, error){
-	if zktx.SequenceNumber == nil || zktx.SequenceNumberAfter == nil{
-		fmt.Println("SequenceNumber or SequenceNumberAfter nil")
-		return common.Hash{},nil
+// GetIncomingNotes returns the notes sent to an unlocked account, as found by
+// the note scanner, and whether they have been deposited.
+func (api *PublicEthereumAPI) GetIncomingNotes(address common.Address) ([]rpctypes.IncomingNote, error) {
+	api.logger.Debug("eth_getIncomingNotes", "address", address)
+	if _, exist := rpctypes.GetKeyByAddress(api.keys, address); !exist {
+		return nil, keystore.ErrLocked
 	}
-	if zktx.SNfile == nil{
-		fmt.Println("SNfile does not exist")
-		return common.Hash{},nil
+
+	notes := api.notes.Lock(address)
+	defer api.notes.Unlock(address)
+
+	incoming := make([]rpctypes.IncomingNote, len(notes.Received))
+	for i, note := range notes.Received {
+		incoming[i] = rpctypes.IncomingNote{
+			TxHash:    note.TxHash,
+			CMTS:      note.CMTS,
+			Value:     hexutil.Uint64(note.Value),
+			Deposited: note.Deposited,
+		}
+	}
+	return incoming, nil
+}
+
+// GetShieldedBalance returns the shielded balance of an unlocked account,
+// together with its unspent notes, after syncing them with the chain.
+func (api *PublicEthereumAPI) GetShieldedBalance(address common.Address) (*rpctypes.ShieldedBalance, error) {
+	api.logger.Debug("eth_getShieldedBalance", "address", address)
+	if _, exist := rpctypes.GetKeyByAddress(api.keys, address); !exist {
+		return nil, keystore.ErrLocked
+	}
+
+	notes := api.notes.Lock(address)
+	defer api.notes.Unlock(address)
+
+	if _, err := api.syncNotes(address, notes); err != nil {
+		return nil, err
+	}
+
+	confirmed, pending := notes.Balance()
+	balance := &rpctypes.ShieldedBalance{
+		Confirmed: hexutil.Uint64(confirmed),
+		Pending:   hexutil.Uint64(pending),
+		Notes:     []rpctypes.ShieldedNote{},
+	}
+
+	available := make(map[common.Hash]bool)
+	for _, note := range notes.Available() {
+		available[*note.CMT] = true
+	}
+	for _, note := range notes.Unspent {
+		if note.Value == 0 {
+			continue
+		}
+		balance.Notes = append(balance.Notes, rpctypes.ShieldedNote{
+			CMT:      *note.CMT,
+			Value:    hexutil.Uint64(note.Value),
+			Spending: !available[*note.CMT],
+		})
+	}
+	return balance, nil
+}
+
+// ResyncShieldedAccount rebuilds the shielded wallet of an unlocked account by
+// replaying its shielded transactions from the chain history, and returns its
+// resulting shielded balance.
+func (api *PublicEthereumAPI) ResyncShieldedAccount(address common.Address) (*rpctypes.ShieldedBalance, error) {
+	api.logger.Debug("eth_resyncShieldedAccount", "address", address)
+	if _, exist := rpctypes.GetKeyByAddress(api.keys, address); !exist {
+		return nil, keystore.ErrLocked
+	}
+
+	if err := api.scanner.Resync(address); err != nil {
+		return nil, err
+	}
+	return api.GetShieldedBalance(address)
+}
+
+// getNotes returns the commitments of the unspent notes of an account on chain,
+// without the initial note.
+func (api *PublicEthereumAPI) getNotes(address common.Address) ([]common.Hash, error) {
+	res, _, err := api.clientCtx.QueryWithData(fmt.Sprintf("custom/%s/%s/%s", evmtypes.ModuleName, evmtypes.QueryNotes, address.Hex()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var out evmtypes.QueryResNotes
+	if err := json.Unmarshal(res, &out); err != nil {
+		return nil, err
+	}
+	return out.Notes, nil
+}
+
+// syncNotes updates the notes of an account with its unspent notes on chain,
+// confirming or dropping its pending transactions, and saves them. It returns
+// the latest block height.
+func (api *PublicEthereumAPI) syncNotes(address common.Address, notes *zktx.AccountNotes) (int64, error) {
+	height, err := api.BlockNumber()
+	if err != nil {
+		return 0, err
+	}
+	cmts, err := api.getNotes(address)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := notes.Sync(cmts, int64(height)); err != nil {
+		return 0, err
+	}
+	return int64(height), api.notes.Save(address, notes)
+}
+
+// GetCommitmentProof returns the authentication path of a send commitment in
+// its commitment tree at the given block number, along with the leaves of that
+// tree, used to build deposit proofs.
+func (api *PublicEthereumAPI) GetCommitmentProof(cmts common.Hash, blockNum rpctypes.BlockNumber) (*rpctypes.CommitmentProof, error) {
+	api.logger.Debug("eth_getCommitmentProof", "cmts", cmts, "block number", blockNum)
+	clientCtx := api.clientCtx.WithHeight(blockNum.Int64())
+	res, _, err := clientCtx.QueryWithData(fmt.Sprintf("custom/%s/%s/%s", evmtypes.ModuleName, evmtypes.QueryCommitmentProof, cmts.Hex()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var out evmtypes.QueryResCommitmentProof
+	if err := json.Unmarshal(res, &out); err != nil {
+		return nil, err
+	}
+
+	return &rpctypes.CommitmentProof{
+		CMTS:   out.CMTS,
+		Tree:   hexutil.Uint64(out.Tree),
+		Index:  hexutil.Uint64(out.Index),
+		Path:   out.Path,
+		Leaves: out.Leaves,
+		Root:   out.Root,
+		Size:   hexutil.Uint64(out.Size),
+		TxHash: out.TxHash,
+	}, nil
+}
+
+func (api *PublicEthereumAPI) SendMintTransaction(args rpctypes.SendTxArgs) (common.Hash, error){
+	notes := api.notes.Lock(args.From)
+	defer api.notes.Unlock(args.From)
+
+	height, err := api.syncNotes(args.From, notes)
+	if err != nil {
+		return common.Hash{}, err
 	}
 
 	//state := evm.NewKeeper(nil, nil, params.Subspace{}, nil)
@@ -1057,7 +1237,7 @@ func (api *PublicEthereumAPI) SendMintTransaction(args rpctypes.SendTxArgs) (com
 
 	args.To = &zktx.ZKTxAddress
 	// Assemble transaction from fields
-	tx, err := api.generateFromArgs(args)
+	tx, err := api.generateShieldedFromArgs(args, evmtypes.MintTx)
 
 	if err != nil {
 		api.logger.Debug("failed to generate tx", "error", err)
@@ -1070,20 +1250,23 @@ func (api *PublicEthereumAPI) SendMintTransaction(args rpctypes.SendTxArgs) (com
 	tx.SetZKValue(args.Value.ToInt().Uint64())
 	//tx.SetPrice(big.NewInt(0))
 	tx.SetValue(big.NewInt(0))
-	tx.SetZKAddress(&zktx.ZKTxAddress)
 
-	SN := zktx.SequenceNumberAfter
+	// the minted value goes to a new note spending the initial note, so that
+	// the mint doesn't conflict with the other transactions of the account
+	SN, err := zktx.InitializeSN()
+	if err != nil {
+		return common.Hash{}, err
+	}
 	tx.SetZKSN(SN.SN) //SN
+	tx.SetZKCMTOld(SN.CMT)
 
-	// Obtaining SK should be done as follows:
-	// key, err := s.GetKey(ctx, address, passwd)
-	// SK := key.PrivateKey
-
-	// For large-scale test, we suppose that SK = CRH(addr), there is impossible in pratical.
-	SK_addr := zktx.ZKTxAddress.Hash()
-	SK := &SK_addr
+	shielded := zktx.NewShieldedKeys(key.ToECDSA())
+	SK, err := shielded.SK(SN)
+	if err != nil {
+		return common.Hash{}, err
+	}
 	newRandom := zktx.NewRandomHash()
-	newSN := zktx.ComputePRF(SK.Bytes(), newRandom.Bytes()) // sn = PRF(sk, r)
+	newSN := zktx.ComputePRF(shielded.NullifierKey.Bytes(), newRandom.Bytes()) // sn = PRF(nk, r)
 	newValue := SN.Value + args.Value.ToInt().Uint64()
 
 	newCMT := zktx.GenCMT(newValue, newSN.Bytes(), newRandom.Bytes()) //tbd
@@ -1096,15 +1279,11 @@ func (api *PublicEthereumAPI) SendMintTransaction(args rpctypes.SendTxArgs) (com
 	}
 
 	//genProofStart := time.Now()
-	zkProof := zktx.GenMintProof(SN.Value, SN.Random, newSN, newRandom, SN.CMT, SN.SN, newCMT, newValue, SK)
-	if err != nil{
-		fmt.Println(err)
-	}
+	zkProof, err := api.prover.GenMintProof(SN.Value, SN.Random, newSN, newRandom, SN.CMT, SN.SN, newCMT, newValue, SK)
 	//genProofEnd := time.Now()
 	// fmt.Println("***** GenMintProof Cost Time (ms): ", genProofEnd.Sub(genProofStart).Nanoseconds() / 1000000)
-	//fmt.Println(zkProof)
-	if string(zkProof[0:10]) == "0000000000" {
-		return common.Hash{}, errors.New("can't generate proof")
+	if err != nil {
+		return common.Hash{}, err
 	}
 
 	tx.SetZKProof(zkProof) //proof tbd
@@ -1115,89 +1294,44 @@ func (api *PublicEthereumAPI) SendMintTransaction(args rpctypes.SendTxArgs) (com
 		api.logger.Debug("failed to sign tx", "error", err)
 		return common.Hash{}, err
 	}
-	txEncoder := authclient.GetTxEncoder(api.clientCtx.Codec)
-	txBytes, err := txEncoder(tx)
-	///////
-	//ethTx, err := rpctypes.RawTxToEthTx(api.clientCtx, txBytes)
-	if err != nil {
-		fmt.Println(err)
-		return common.Hash{}, err
-	}
-	// Broadcast transaction in sync mode (default)
-	// NOTE: If error is encountered on the node, the broadcast will not return an error
-	res, err := api.clientCtx.BroadcastTx(txBytes)
-	fmt.Println(res)
-	if err != nil {
-		return common.Hash{}, err
-	}
-	if err == nil {
-		zktx.SequenceNumber = zktx.SequenceNumberAfter
-		zktx.SequenceNumberAfter = &zktx.Sequence{SN: newSN, CMT: newCMT, Random: newRandom, Value: newValue}
-		zktx.Stage = zktx.Mint
-		SNS := zktx.SequenceS{*zktx.SequenceNumber, *zktx.SequenceNumberAfter, zktx.SNS, nil, nil, zktx.Mint}
-		SNSBytes, err := rlp.EncodeToBytes(SNS)
-
-		if err != nil {
-			fmt.Println("encode sns error")
-			return common.Hash{}, nil
-		}
-		SNSString := hex.EncodeToString(SNSBytes)
-		zktx.SNfile.Seek(0, 0) //write in the first line of the file
-		wt := bufio.NewWriter(zktx.SNfile)
 
-		wt.WriteString(SNSString)
-		wt.WriteString("\n") //write a line
-		wt.Flush()
-	}
-
-	//fmt.Println("***** mint transaction size: ", signed.Size())
-
-	return common.HexToHash(res.TxHash), nil
+	newNote := &zktx.Sequence{SN: newSN, CMT: newCMT, Random: newRandom, Value: newValue}
+	return api.broadcastShieldedTx(args.From, notes, tx, SN, newNote, height)
 }
 
 
 // SendSendTransaction creates a send transaction for the given argument, sign it and submit it to the
-// transaction pool.
+// transaction pool. It spends the smallest available note of args.From holding args.Value.
 func (api *PublicEthereumAPI) SendSendTransaction(ctx context.Context, args rpctypes.SendTxArgs) (common.Hash, error) { //tbd
 	// if zktx.Stage == zktx.Send {
 	// 	fmt.Println("cannot send sendTx after sendTx")
 	// 	return common.Hash{}, nil
 	// }
-	if zktx.SNfile == nil {
-		fmt.Println("SNfile does not exist")
-		return common.Hash{}, nil
+	if args.Value == nil || args.PubKey == nil {
+		return common.Hash{}, errors.New("missing send value or receiver pubKey")
 	}
 
-	if zktx.SequenceNumber == nil || zktx.SequenceNumberAfter == nil {
-		fmt.Println("SequenceNumber or SequenceNumberAfter nil")
-		return common.Hash{}, nil
+	type pub struct {
+		X *big.Int
+		Y *big.Int
 	}
-	//state, _, err := api.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
-	//if state == nil || err != nil {
-	//	return common.Hash{}, err
-	//}
 
-	//check whether sn can be used
-	exist,err:= api.GetSN(zktx.SequenceNumberAfter.SN)
-
-	if exist == true && *(zktx.SequenceNumberAfter.SN) != *(zktx.InitializeSN().SN) {
-		fmt.Println("sn is lost")
-		return common.Hash{}, nil
+	var pubKey pub
+	if err := rlp.DecodeBytes(*args.PubKey, &pubKey); err != nil { //--zy
+		return common.Hash{}, fmt.Errorf("invalid receiver pubKey: %w", err)
 	}
+	receiverPubkey := &ecdsa.PublicKey{Curve: crypto.S256(), X: pubKey.X, Y: pubKey.Y}
 
-	//check whether last tx is processed successfully
-	//exist = state.Exist(common.BytesToAddress(zktx.SequenceNumber.SN.Bytes()))
-	//
-	if exist == false && *(zktx.SequenceNumber.SN) != *(zktx.InitializeSN().SN) { //if last transaction is not processed successfully, the corresponding SN is not in the database,and we use SN before  last unprocessed transaction
-		// if zktx.Stage == zktx.Update {
-		// 	fmt.Println("last transaction is update,but it is not well processed,please send updateTx firstly")
-		// 	return common.Hash{}, nil
-		// }
-		zktx.SequenceNumberAfter = zktx.SequenceNumber
+	notes := api.notes.Lock(args.From)
+	defer api.notes.Unlock(args.From)
+
+	// sync the notes with the chain so that only unspent notes are selected
+	height, err := api.syncNotes(args.From, notes)
+	if err != nil {
+		return common.Hash{}, err
 	}
 
 	// Look up the wallet containing the requested signer
-	account := accounts.Account{Address: args.From}
 	key, exist := rpctypes.GetKeyByAddress(api.keys, args.From)
 	if !exist {
 		api.logger.Debug("failed to find key in keyring", "key", args.From)
@@ -1210,42 +1344,50 @@ func (api *PublicEthereumAPI) SendSendTransaction(ctx context.Context, args rpct
 		api.nonceLock.LockAddr(args.From)
 		defer api.nonceLock.UnlockAddr(args.From)
 	}
+
+	SN, err := notes.SelectNote(args.Value.ToInt().Uint64())
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	txHash, _, err := api.sendNote(args, key, notes, SN, receiverPubkey, height)
+	return txHash, err
+}
+
+// sendNote creates a send transaction of args.Value from the given note of
+// args.From to the receiver public key, signs it and submits it to the
+// transaction pool. It returns the hash of the transaction and the transaction.
+func (api *PublicEthereumAPI) sendNote(
+	args rpctypes.SendTxArgs, key *ethsecp256k1.PrivKey, notes *zktx.AccountNotes, SN *zktx.Sequence,
+	receiverPubkey *ecdsa.PublicKey, height int64,
+) (common.Hash, *evmtypes.MsgEthereumTx, error) {
+	account := accounts.Account{Address: args.From}
+	value := args.Value.ToInt().Uint64()
+
 	args.To = &zktx.ZKTxAddress
 	// Set some sanity defaults and terminate on failure
 	// Assemble the transaction and sign with the wallet
-	tx, _ := api.generateFromArgs(args)
+	tx, err := api.generateShieldedFromArgs(args, evmtypes.SendTx)
+	if err != nil {
+		api.logger.Debug("failed to generate tx", "error", err)
+		return common.Hash{}, nil, err
+	}
 	tx.SetTxCode(evmtypes.SendTx)
 	tx.SetPrice(big.NewInt(0))
 	tx.SetValue(big.NewInt(0))
 	// randomAddress := zktx.NewRandomAddress()
-	tx.SetZKAddress(&zktx.ZKTxAddress)
 	//tx.SetNonce(0)
 
-	SN := zktx.SequenceNumberAfter
 	tx.SetZKSN(SN.SN) //SN
-	//
-	//if len(*args.To) != PubKeySize {
-	//	return common.Hash{}, errors.New("invalid receiver pubkey")
-	//}
-
-	type pub struct {
-		X *big.Int
-		Y *big.Int
-	}
+	tx.SetZKCMTOld(SN.CMT)
 
 	//genRandomKeyStart := time.Now()
-	var pubKey pub
-
-	rlp.DecodeBytes(*args.PubKey, &pubKey) //--zy
-
-	receiverPubkey := &ecdsa.PublicKey{crypto.S256(), pubKey.X, pubKey.Y}
-
 	R := zktx.GenR()
 	Sa := R.D
 
 	randomReceiverPK := zktx.NewRandomPubKey(Sa, *receiverPubkey)
 
-	zktx.RandomReceiverPK = randomReceiverPK //store randomReceiverPK for update
+	notes.RandomReceiverPK = randomReceiverPK //store randomReceiverPK for update
 
 	//genRandomKeyEnd := time.Now()
 	// fmt.Println("***** GenRandomKey Cost Time (ms): ", genRandomKeyEnd.Sub(genRandomKeyStart).Nanoseconds() / 1000000)
@@ -1259,79 +1401,394 @@ func (api *PublicEthereumAPI) SendSendTransaction(ctx context.Context, args rpct
 	//SNs := zktx.NewRandomHash()
 	newRs := zktx.ComputeCRH(account.Address, newRandomA.Bytes()) // A 新 r_s = CRH(pk, r)
 
-	CMTs := zktx.GenCMTS(args.Value.ToInt().Uint64(), randomReceiverPK, newRs.Bytes(), SN.SN.Bytes()) //生成cmts
+	CMTs := zktx.GenCMTS(value, randomReceiverPK, newRs.Bytes(), SN.SN.Bytes()) //生成cmts
 	tx.SetZKCMTS(CMTs)
 
-	// Obtaining SK should be done as follows:
-	// key, err := s.GetKey(ctx, address, passwd)
-	// SK := key.PrivateKey
-
-	// For large-scale test, we suppose that SK = CRH(addr), there is impossible in pratical.
 	PK_sender := account.Address
-	SK_addr := zktx.ZKTxAddress.Hash()
-	SK := &SK_addr
+	shielded := zktx.NewShieldedKeys(key.ToECDSA())
+	SK, err := shielded.SK(SN)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
 
-	newSNA := zktx.ComputePRF(SK.Bytes(), newRandomA.Bytes()) // A新sn = PRF(sk, r)
+	newSNA := zktx.ComputePRF(shielded.NullifierKey.Bytes(), newRandomA.Bytes()) // A新sn = PRF(nk, r)
 
-	newValueA := SN.Value - args.Value.ToInt().Uint64()                   //update后 A新value
+	newValueA := SN.Value - value                                         //update后 A新value
 	newCMTA := zktx.GenCMT(newValueA, newSNA.Bytes(), newRandomA.Bytes()) //A 新 cmt
 	tx.SetZKCMT(newCMTA)
-	//tx.SetZKAddress(&args.From)
 	//end
 	//genProofStart := time.Now()
-	zkProof := zktx.GenSendProof(SN.CMT, SN.Value, SN.Random, args.Value.ToInt().Uint64(), randomReceiverPK, newRs, SN.SN, CMTs, newValueA, newSNA, newRandomA, newCMTA, SK, PK_sender)
+	zkProof, err := api.prover.GenSendProof(SN.CMT, SN.Value, SN.Random, value, randomReceiverPK, newRs, SN.SN, CMTs, newValueA, newSNA, newRandomA, newCMTA, SK, PK_sender)
 	//genProofEnd := time.Now()
 	// fmt.Println("***** GenSendProof Cost Time (ms): ", genProofEnd.Sub(genProofStart).Nanoseconds() / 1000000)
-	if string(zkProof[0:10]) == "0000000000" {
-		return common.Hash{}, errors.New("can't generate proof")
+	if err != nil {
+		return common.Hash{}, nil, err
 	}
 	tx.SetZKProof(zkProof) //proof tbd
-	AUX := zktx.ComputeAUX(randomReceiverPK, args.Value.ToInt().Uint64(), newRs, SN.SN)
+	AUX, err := zktx.ComputeAUX(randomReceiverPK, value, newRs, SN.SN)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
 	//fmt.Println("***** Compute AUX size: ", len(AUX))
 
 	tx.SetAUX(AUX)
-	zktx.SNS = &zktx.Sequence{SN: &common.Hash{}, CMT: CMTs, Random: newRs, Value: args.Value.ToInt().Uint64()}
+	notes.SNS = &zktx.Sequence{SN: &common.Hash{}, CMT: CMTs, Random: newRs, Value: value}
 
 	var chainID *big.Int
 	chainID = api.chainIDEpoch
 
 	if err := tx.Sign(chainID, key.ToECDSA()); err != nil {
 		api.logger.Debug("failed to sign tx", "error", err)
-		return common.Hash{}, err
+		return common.Hash{}, nil, err
 	}
+
+	newNote := &zktx.Sequence{SN: newSNA, CMT: newCMTA, Random: newRandomA, Value: newValueA}
+	txHash, err := api.broadcastShieldedTx(args.From, notes, tx, SN, newNote, height)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+
+	//fmt.Println("***** send transaction size: ", tx.Size())
+
+	return txHash, tx, nil
+}
+
+// broadcastShieldedTx submits a signed shielded transaction of an account,
+// spending the note spent and creating the note created, to the transaction
+// pool and records it as pending in the notes of the account.
+func (api *PublicEthereumAPI) broadcastShieldedTx(
+	address common.Address, notes *zktx.AccountNotes, tx *evmtypes.MsgEthereumTx, spent, created *zktx.Sequence, height int64,
+) (common.Hash, error) {
 	txEncoder := authclient.GetTxEncoder(api.clientCtx.Codec)
 	txBytes, err := txEncoder(tx)
-	///////
-	//ethTx, err := rpctypes.RawTxToEthTx(api.clientCtx, txBytes)
 	if err != nil {
-		fmt.Println(err)
 		return common.Hash{}, err
 	}
+
+	// save the new note before broadcasting, so that a resync can recover it
+	notes.Track(created)
+	if err := api.notes.Save(address, notes); err != nil {
+		return common.Hash{}, err
+	}
+
 	// Broadcast transaction in sync mode (default)
 	// NOTE: If error is encountered on the node, the broadcast will not return an error
 	res, err := api.clientCtx.BroadcastTx(txBytes)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if res.Code != abci.CodeTypeOK {
+		return common.Hash{}, fmt.Errorf("transaction %s rejected: %s", res.TxHash, res.RawLog)
+	}
 
-	if err == nil {
-		zktx.Stage = zktx.Send
-		zktx.SequenceNumber = zktx.SequenceNumberAfter
-		zktx.SequenceNumberAfter = &zktx.Sequence{SN: newSNA, CMT: newCMTA, Random: newRandomA, Value: newValueA}
-		SNS := zktx.SequenceS{*zktx.SequenceNumber, *zktx.SequenceNumberAfter, zktx.SNS, nil, nil, zktx.Send}
-		SNSBytes, err := rlp.EncodeToBytes(SNS)
-		if err != nil {
-			fmt.Println("encode sns error")
-			return common.Hash{}, nil
+	notes.AddPending(spent, created, height)
+	if err := api.notes.Save(address, notes); err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.HexToHash(res.TxHash), nil
+}
+
+// SendDepositTransaction creates a deposit transaction claiming the incoming send
+// transaction identified by args.TxHash, either its tx hash or its send
+// commitment, into a new note of args.From. It signs it and submits it to the
+// transaction pool.
+func (api *PublicEthereumAPI) SendDepositTransaction(args rpctypes.SendTxArgs) (common.Hash, error) {
+	api.logger.Debug("eth_sendDepositTransaction", "from", args.From, "hash", args.TxHash)
+	sendTx, sendTxHash, err := api.getSendTransaction(args.TxHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	notes := api.notes.Lock(args.From)
+	defer api.notes.Unlock(args.From)
+
+	height, err := api.syncNotes(args.From, notes)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	key, exist := rpctypes.GetKeyByAddress(api.keys, args.From)
+	if !exist {
+		api.logger.Debug("failed to find key in keyring", "key", args.From)
+		return common.Hash{}, keystore.ErrLocked
+	}
+
+	if args.Nonce == nil {
+		// Hold the addresse's mutex around signing to prevent concurrent assignment of
+		// the same nonce to multiple accounts.
+		api.nonceLock.LockAddr(args.From)
+		defer api.nonceLock.UnlockAddr(args.From)
+	}
+
+	// the received value goes to a new note spending the initial note, so that
+	// the deposit doesn't conflict with the other transactions of the account
+	initial, err := zktx.InitializeSN()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return api.depositNote(args, key, notes, initial, sendTx, sendTxHash, height)
+}
+
+// depositNote creates a deposit transaction claiming the incoming send
+// transaction sendTx into the given note of args.From, signs it and submits it
+// to the transaction pool.
+func (api *PublicEthereumAPI) depositNote(
+	args rpctypes.SendTxArgs, key *ethsecp256k1.PrivKey, notes *zktx.AccountNotes, SN *zktx.Sequence,
+	sendTx *evmtypes.MsgEthereumTx, sendTxHash common.Hash, height int64,
+) (common.Hash, error) {
+	cmtProof, err := api.GetCommitmentProof(*sendTx.ZKCMTS(), rpctypes.LatestBlockNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	// the AUX is encrypted with the one-time public key of the receiver, derived
+	// from its incoming viewing key and the public key R of the send transaction
+	shielded := zktx.NewShieldedKeys(key.ToECDSA())
+	R := &ecdsa.PublicKey{Curve: crypto.S256(), X: sendTx.X(), Y: sendTx.Y()}
+	randomKeyB := zktx.GenerateKeyForRandomB(R, shielded.IncomingViewingKey)
+	valueS, RS, SNA, err := zktx.DecAUX(randomKeyB, sendTx.AUX())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("send transaction %s is not addressed to %s: %w", sendTxHash.Hex(), args.From.Hex(), err)
+	}
+
+	args.To = &zktx.ZKTxAddress
+	args.Value = (*hexutil.Big)(big.NewInt(0))
+	tx, err := api.generateShieldedFromArgs(args, evmtypes.DepositTx)
+	if err != nil {
+		api.logger.Debug("failed to generate tx", "error", err)
+		return common.Hash{}, err
+	}
+
+	tx.SetTxCode(evmtypes.DepositTx)
+	tx.SetValue(big.NewInt(0))
+	tx.SetPubKey(randomKeyB.PublicKey.X, randomKeyB.PublicKey.Y)
+	tx.SetRTcmt(cmtProof.Root)
+
+	tx.SetZKSN(SN.SN) //SN
+	tx.SetZKCMTOld(SN.CMT)
+
+	SK, err := shielded.SK(SN)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	SNS := zktx.ComputePRF(SK.Bytes(), RS.Bytes()) // sns = PRF(sk, rs)
+	tx.SetZKSNS(SNS)
+
+	newRandomB := zktx.NewRandomHash()
+	newSNB := zktx.ComputePRF(shielded.NullifierKey.Bytes(), newRandomB.Bytes()) // sn = PRF(nk, r)
+	newValueB := SN.Value + valueS
+	newCMTB := zktx.GenCMT(newValueB, newSNB.Bytes(), newRandomB.Bytes())
+	tx.SetZKCMT(newCMTB)
+
+	// the deposit circuit rebuilds the root from the leaves of the tree holding
+	// the send commitment, in leaf order, and checks that it is one of them
+	if uint64(cmtProof.Index) >= uint64(len(cmtProof.Leaves)) || cmtProof.Leaves[cmtProof.Index] != *sendTx.ZKCMTS() {
+		return common.Hash{}, fmt.Errorf("send commitment %s isn't leaf %d of commitment tree %d", sendTx.ZKCMTS().Hex(), cmtProof.Index, cmtProof.Tree)
+	}
+	leaves := make([]*common.Hash, len(cmtProof.Leaves))
+	for i := range cmtProof.Leaves {
+		leaves[i] = &cmtProof.Leaves[i]
+	}
+
+	zkProof, err := api.prover.GenDepositProof(sendTx.ZKCMTS(), valueS, SNS, RS, SNA, SN.Value, SN.Random, newSNB, newRandomB, &randomKeyB.PublicKey, cmtProof.Root.Bytes(), SN.CMT, SN.SN, newCMTB, leaves, SK)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx.SetZKProof(zkProof)
+
+	// the one-time key proves that the receiver can spend the send commitment
+	if err := tx.SignDeposit(api.chainIDEpoch, randomKeyB); err != nil {
+		api.logger.Debug("failed to sign deposit", "error", err)
+		return common.Hash{}, err
+	}
+	if err := tx.Sign(api.chainIDEpoch, key.ToECDSA()); err != nil {
+		api.logger.Debug("failed to sign tx", "error", err)
+		return common.Hash{}, err
+	}
+
+	newNote := &zktx.Sequence{SN: newSNB, CMT: newCMTB, Random: newRandomB, Value: newValueB}
+	txHash, err := api.broadcastShieldedTx(args.From, notes, tx, SN, newNote, height)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	received := notes.AddReceived(zktx.ReceivedNote{
+		TxHash: sendTxHash, CMTS: *sendTx.ZKCMTS(), Value: valueS, RS: *RS, SNA: *SNA,
+		Key: crypto.PubkeyToAddress(randomKeyB.PublicKey),
+	})
+	received.Deposited = true
+	if err := api.notes.Save(args.From, notes); err != nil {
+		return common.Hash{}, err
+	}
+
+	return txHash, nil
+}
+
+// MergeNotes consolidates the two available notes of an unlocked account with
+// the smallest values: the smallest one is sent in full to the account itself
+// and, once the send transaction is committed, deposited into the other one.
+// It returns the hash of the deposit transaction.
+func (api *PublicEthereumAPI) MergeNotes(address common.Address) (common.Hash, error) {
+	api.logger.Debug("eth_mergeNotes", "address", address)
+	key, exist := rpctypes.GetKeyByAddress(api.keys, address)
+	if !exist {
+		return common.Hash{}, keystore.ErrLocked
+	}
+
+	notes := api.notes.Lock(address)
+	defer api.notes.Unlock(address)
+
+	height, err := api.syncNotes(address, notes)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	from, into, ok := notes.SelectMerge()
+	if !ok {
+		return common.Hash{}, errors.New("fewer than two notes to merge")
+	}
+
+	api.nonceLock.LockAddr(address)
+	defer api.nonceLock.UnlockAddr(address)
+
+	args := rpctypes.SendTxArgs{From: address, Value: (*hexutil.Big)(new(big.Int).SetUint64(from.Value))}
+	self := zktx.NewShieldedKeys(key.ToECDSA()).IncomingViewingKey.PublicKey
+	sendTxHash, sendTx, err := api.sendNote(args, key, notes, from, &self, height)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	// the deposit proves the send commitment against a root of the commitment
+	// tree, recorded once the block of the send transaction is committed
+	if err := api.waitForCommitment(*sendTx.ZKCMTS(), mergeTimeout); err != nil {
+		return common.Hash{}, fmt.Errorf("send transaction %s of the merge: %w", sendTxHash.Hex(), err)
+	}
+
+	if height, err = api.syncNotes(address, notes); err != nil {
+		return common.Hash{}, err
+	}
+	return api.depositNote(rpctypes.SendTxArgs{From: address}, key, notes, into, sendTx, sendTxHash, height)
+}
+
+// waitForCommitment waits until the send commitment is in the commitment tree.
+func (api *PublicEthereumAPI) waitForCommitment(cmts common.Hash, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		_, err := api.GetCommitmentProof(cmts, rpctypes.LatestBlockNumber)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("send commitment %s not committed after %s: %w", cmts.Hex(), timeout, err)
 		}
-		SNSString := hex.EncodeToString(SNSBytes)
-		zktx.SNfile.Seek(0, 0) //write in the first line of the file
-		wt := bufio.NewWriter(zktx.SNfile)
+		time.Sleep(time.Second)
+	}
+}
 
-		wt.WriteString(SNSString)
-		wt.WriteString("\n") //write a line
-		wt.Flush()
+// getSendTransaction returns the send transaction identified by either its tx
+// hash or its send commitment, together with its tx hash.
+func (api *PublicEthereumAPI) getSendTransaction(hash common.Hash) (*evmtypes.MsgEthereumTx, common.Hash, error) {
+	txHash := hash
+	if cmtProof, err := api.GetCommitmentProof(hash, rpctypes.LatestBlockNumber); err == nil {
+		if cmtProof.TxHash == (common.Hash{}) {
+			return nil, common.Hash{}, fmt.Errorf("send transaction of commitment %s not found", hash.Hex())
+		}
+		txHash = cmtProof.TxHash
 	}
 
-	//fmt.Println("***** send transaction size: ", tx.Size())
+	res, err := api.clientCtx.Client.Tx(txHash.Bytes(), false)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("send transaction %s not found: %w", txHash.Hex(), err)
+	}
+
+	ethTx, err := rpctypes.RawTxToEthTx(api.clientCtx, res.Tx)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	if ethTx.TxCode() != evmtypes.SendTx {
+		return nil, common.Hash{}, fmt.Errorf("transaction %s is not a send transaction", txHash.Hex())
+	}
+	return ethTx, txHash, nil
+}
+
+// SendRedeemTransaction creates a redeem transaction moving args.Value from the
+// shielded balance of args.From back to its public balance, signs it and submits
+// it to the transaction pool. It spends the smallest available note of args.From
+// holding args.Value.
+func (api *PublicEthereumAPI) SendRedeemTransaction(args rpctypes.SendTxArgs) (common.Hash, error) {
+	api.logger.Debug("eth_sendRedeemTransaction", "from", args.From, "value", args.Value)
+	if args.Value == nil {
+		return common.Hash{}, errors.New("missing redeem value")
+	}
+
+	notes := api.notes.Lock(args.From)
+	defer api.notes.Unlock(args.From)
+
+	height, err := api.syncNotes(args.From, notes)
+	if err != nil {
+		return common.Hash{}, err
+	}
 
-	return common.HexToHash(res.TxHash), err
+	key, exist := rpctypes.GetKeyByAddress(api.keys, args.From)
+	if !exist {
+		api.logger.Debug("failed to find key in keyring", "key", args.From)
+		return common.Hash{}, keystore.ErrLocked
+	}
+
+	// Mutex lock the address' nonce to avoid assigning it to multiple requests
+	if args.Nonce == nil {
+		api.nonceLock.LockAddr(args.From)
+		defer api.nonceLock.UnlockAddr(args.From)
+	}
+
+	value := args.Value.ToInt()
+	if !value.IsUint64() {
+		return common.Hash{}, zktx.ErrInsufficientShieldedBalance
+	}
+	SN, err := notes.SelectNote(value.Uint64())
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	args.To = &zktx.ZKTxAddress
+	// Assemble transaction from fields
+	tx, err := api.generateShieldedFromArgs(args, evmtypes.RedeemTx)
+	if err != nil {
+		api.logger.Debug("failed to generate tx", "error", err)
+		return common.Hash{}, err
+	}
+
+	tx.SetTxCode(evmtypes.RedeemTx)
+	tx.SetZKValue(value.Uint64())
+	tx.SetValue(big.NewInt(0))
+	tx.SetZKSN(SN.SN) //SN
+	tx.SetZKCMTOld(SN.CMT)
+
+	shielded := zktx.NewShieldedKeys(key.ToECDSA())
+	SK, err := shielded.SK(SN)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	newRandom := zktx.NewRandomHash()
+	newSN := zktx.ComputePRF(shielded.NullifierKey.Bytes(), newRandom.Bytes()) // sn = PRF(nk, r)
+	newValue := SN.Value - value.Uint64()
+
+	newCMT := zktx.GenCMT(newValue, newSN.Bytes(), newRandom.Bytes())
+	tx.SetZKCMT(newCMT) //cmt
 
-}
\ No newline at end of file
+	zkProof, err := api.prover.GenRedeemProof(SN.Value, SN.Random, newSN, newRandom, SN.CMT, SN.SN, newCMT, newValue, SK)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx.SetZKProof(zkProof)
+
+	if err := tx.Sign(api.chainIDEpoch, key.ToECDSA()); err != nil {
+		api.logger.Debug("failed to sign tx", "error", err)
+		return common.Hash{}, err
+	}
+
+	newNote := &zktx.Sequence{SN: newSN, CMT: newCMT, Random: newRandom, Value: newValue}
+	return api.broadcastShieldedTx(args.From, notes, tx, SN, newNote, height)
+}
@@ -0,0 +1,279 @@
+package eth
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	clientcontext "github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/cosmos/ethermint/crypto/ethsecp256k1"
+	rpctypes "github.com/cosmos/ethermint/rpc/types"
+	ethermint "github.com/cosmos/ethermint/types"
+	evmtypes "github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// scanInterval is the delay between two scans for new blocks
+	scanInterval = 5 * time.Second
+
+	// scanBatchSize is the number of blocks scanned before the received notes
+	// are saved to the wallet
+	scanBatchSize = 100
+)
+
+// NoteScanner walks the committed blocks and records the notes that send
+// transactions address to the unlocked accounts in their shielded wallet. It
+// also resynchronises the wallet of an account with its shielded transactions.
+type NoteScanner struct {
+	clientCtx    clientcontext.CLIContext
+	chainIDEpoch *big.Int
+	notes        *zktx.NoteStore
+	accounts     []scannedAccount
+	logger       log.Logger
+}
+
+type scannedAccount struct {
+	address common.Address
+	keys    *zktx.ShieldedKeys
+}
+
+// scanResult holds what the scanned blocks hold for an account.
+type scanResult struct {
+	received  []zktx.ReceivedNote
+	deposited []common.Address
+	txs       []zktx.ShieldedTx
+}
+
+// NewNoteScanner creates a scanner of the notes sent to the given accounts.
+func NewNoteScanner(clientCtx clientcontext.CLIContext, notes *zktx.NoteStore, keys ...ethsecp256k1.PrivKey) *NoteScanner {
+	epoch, err := ethermint.ParseChainID(clientCtx.ChainID)
+	if err != nil {
+		panic(err)
+	}
+
+	accounts := make([]scannedAccount, len(keys))
+	for i, key := range keys {
+		accounts[i] = scannedAccount{
+			address: common.BytesToAddress(key.PubKey().Address().Bytes()),
+			keys:    zktx.NewShieldedKeys(key.ToECDSA()),
+		}
+	}
+
+	return &NoteScanner{
+		clientCtx:    clientCtx,
+		chainIDEpoch: epoch,
+		notes:        notes,
+		accounts:     accounts,
+		logger:       log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "json-rpc", "service", "note-scanner"),
+	}
+}
+
+// Start resynchronises the wallet of every account and then scans the new
+// blocks in the background.
+func (s *NoteScanner) Start() {
+	if len(s.accounts) == 0 {
+		return
+	}
+
+	go func() {
+		for _, account := range s.accounts {
+			if err := s.Resync(account.address); err != nil {
+				s.logger.Error("failed to resync shielded account", "address", account.address, "error", err)
+			}
+		}
+
+		ticker := time.NewTicker(scanInterval)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			if err := s.Scan(); err != nil {
+				s.logger.Error("failed to scan blocks", "error", err)
+			}
+		}
+	}()
+}
+
+// Scan scans the blocks committed since the last scan of each account up to
+// the latest one.
+func (s *NoteScanner) Scan() error {
+	latest, err := s.latestHeight()
+	if err != nil {
+		return err
+	}
+
+	from := latest + 1
+	for _, account := range s.accounts {
+		notes := s.notes.Lock(account.address)
+		if notes.ScanHeight+1 < from {
+			from = notes.ScanHeight + 1
+		}
+		s.notes.Unlock(account.address)
+	}
+
+	for ; from <= latest; from += scanBatchSize {
+		to := from + scanBatchSize - 1
+		if to > latest {
+			to = latest
+		}
+
+		results, err := s.scanBlocks(from, to, s.accounts)
+		if err != nil {
+			return err
+		}
+		for _, account := range s.accounts {
+			if err := s.save(account.address, results[account.address], to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Resync rebuilds the wallet of an unlocked account from the chain history: it
+// replays the shielded transactions of the account to find the notes it holds,
+// and records the notes sent to it and whether they have been deposited.
+func (s *NoteScanner) Resync(address common.Address) error {
+	var account *scannedAccount
+	for i := range s.accounts {
+		if s.accounts[i].address == address {
+			account = &s.accounts[i]
+		}
+	}
+	if account == nil {
+		return fmt.Errorf("account %s is locked", address.Hex())
+	}
+
+	latest, err := s.latestHeight()
+	if err != nil {
+		return err
+	}
+	results, err := s.scanBlocks(1, latest, []scannedAccount{*account})
+	if err != nil {
+		return err
+	}
+	result := results[address]
+
+	notes := s.notes.Lock(address)
+	defer s.notes.Unlock(address)
+
+	if err := notes.Replay(result.txs); err != nil {
+		return err
+	}
+	for _, note := range result.received {
+		notes.AddReceived(note)
+	}
+	markDeposited(notes, result.deposited)
+	notes.ScanHeight = latest
+	return s.notes.Save(address, notes)
+}
+
+func (s *NoteScanner) latestHeight() (int64, error) {
+	info, err := s.clientCtx.Client.BlockchainInfo(0, 0)
+	if err != nil {
+		return 0, err
+	}
+	return info.LastHeight, nil
+}
+
+// scanBlocks returns what the given blocks hold for each account.
+func (s *NoteScanner) scanBlocks(from, to int64, accounts []scannedAccount) (map[common.Address]*scanResult, error) {
+	results := make(map[common.Address]*scanResult)
+	for _, account := range accounts {
+		results[account.address] = new(scanResult)
+	}
+
+	for height := from; height <= to; height++ {
+		h := height
+		block, err := s.clientCtx.Client.Block(&h)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tx := range block.Block.Txs {
+			ethTx, err := rpctypes.RawTxToEthTx(s.clientCtx, tx)
+			if err != nil {
+				continue
+			}
+			// every shielded transaction spends a note of its sender
+			if ethTx.Shielded == nil {
+				continue
+			}
+
+			// the shielded transactions of the account, found by their signer
+			if signer, err := ethTx.VerifySig(s.chainIDEpoch); err == nil {
+				if result, ok := results[signer]; ok {
+					result.txs = append(result.txs, zktx.ShieldedTx{Spent: *ethTx.ZKCMTOld(), CMT: *ethTx.ZKCMT()})
+				}
+			}
+
+			switch data := ethTx.Shielded.(type) {
+			case *evmtypes.SendTxData:
+				if data.X == nil || data.Y == nil {
+					continue
+				}
+				R := &ecdsa.PublicKey{Curve: crypto.S256(), X: data.X, Y: data.Y}
+				for _, account := range accounts {
+					note, ok := account.keys.ReceiveNote(R, data.AUX, data.SN)
+					if !ok {
+						continue
+					}
+
+					note.TxHash = common.BytesToHash(tx.Hash())
+					note.CMTS = data.CMTS
+					results[account.address].received = append(results[account.address].received, note)
+					s.logger.Info("received shielded note", "address", account.address, "tx", note.TxHash, "value", note.Value)
+				}
+
+			case *evmtypes.DepositTxData:
+				if data.X == nil || data.Y == nil {
+					continue
+				}
+				// the deposit reveals the one-time key of the note it claims
+				R := &ecdsa.PublicKey{Curve: crypto.S256(), X: data.X, Y: data.Y}
+				for _, result := range results {
+					result.deposited = append(result.deposited, crypto.PubkeyToAddress(*R))
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// save records the notes received by an account in its wallet, together with
+// the height scanned up to.
+func (s *NoteScanner) save(address common.Address, result *scanResult, height int64) error {
+	notes := s.notes.Lock(address)
+	defer s.notes.Unlock(address)
+
+	if notes.ScanHeight >= height {
+		return nil
+	}
+
+	for _, note := range result.received {
+		notes.AddReceived(note)
+	}
+	markDeposited(notes, result.deposited)
+	notes.ScanHeight = height
+	return s.notes.Save(address, notes)
+}
+
+// markDeposited flags the received notes sent to the given one-time keys as
+// deposited.
+func markDeposited(notes *zktx.AccountNotes, keys []common.Address) {
+	for _, key := range keys {
+		for _, note := range notes.Received {
+			if note.Key == key {
+				note.Deposited = true
+			}
+		}
+	}
+}
@@ -26,6 +26,45 @@ type StorageResult struct {
 	Proof []string     `json:"proof"`
 }
 
+// CommitmentProof defines the format for the authentication path of a send
+// commitment in its commitment tree, along with the leaves of that tree
+type CommitmentProof struct {
+	CMTS   common.Hash    `json:"cmts"`
+	Tree   hexutil.Uint64 `json:"tree"`
+	Index  hexutil.Uint64 `json:"index"`
+	Path   []common.Hash  `json:"path"`
+	Leaves []common.Hash  `json:"leaves"`
+	Root   common.Hash    `json:"root"`
+	Size   hexutil.Uint64 `json:"size"`
+	TxHash common.Hash    `json:"txHash"`
+}
+
+// IncomingNote defines the format of a note sent to an account, found by the
+// note scanner
+type IncomingNote struct {
+	TxHash    common.Hash    `json:"txHash"`
+	CMTS      common.Hash    `json:"cmts"`
+	Value     hexutil.Uint64 `json:"value"`
+	Deposited bool           `json:"deposited"`
+}
+
+// ShieldedBalance defines the format of the shielded balance of an account.
+// Pending is the balance once the unconfirmed shielded transactions of the
+// account are processed, and Notes the unspent notes of the account.
+type ShieldedBalance struct {
+	Confirmed hexutil.Uint64 `json:"confirmed"`
+	Pending   hexutil.Uint64 `json:"pending"`
+	Notes     []ShieldedNote `json:"notes"`
+}
+
+// ShieldedNote defines the format of an unspent note of an account. Spending is
+// whether an unconfirmed shielded transaction of the account spends it.
+type ShieldedNote struct {
+	CMT      common.Hash    `json:"cmt"`
+	Value    hexutil.Uint64 `json:"value"`
+	Spending bool           `json:"spending"`
+}
+
 // Transaction represents a transaction returned to RPC clients.
 type Transaction struct {
 	BlockHash        *common.Hash    `json:"blockHash"`
@@ -33,7 +33,6 @@ func init() {
 type EthAccount struct {
 	*authtypes.BaseAccount `json:"base_account" yaml:"base_account"`
 	CodeHash               []byte `json:"code_hash" yaml:"code_hash"`
-	CMT      ethcmn.Hash
 }
 
 // ProtoAccount defines the prototype function for BaseAccount used for an
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -26,6 +27,7 @@ func GetQueryCmd(moduleName string, cdc *codec.Codec) *cobra.Command {
 	evmQueryCmd.AddCommand(flags.GetCommands(
 		GetCmdGetStorageAt(moduleName, cdc),
 		GetCmdGetCode(moduleName, cdc),
+		GetCmdGetSN(moduleName, cdc),
 	)...)
 	return evmQueryCmd
 }
@@ -86,3 +88,32 @@ func GetCmdGetCode(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+// GetCmdGetSN queries whether a serial number has been spent
+func GetCmdGetSN(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "sn [serial-number]",
+		Short: "Gets whether a serial number has been spent and at which height",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx := context.NewCLIContext().WithCodec(cdc)
+
+			sn, err := types.ParseSN(args[0])
+			if err != nil {
+				return err
+			}
+
+			res, _, err := clientCtx.Query(
+				fmt.Sprintf("custom/%s/%s/%s", queryRoute, types.QueryResSN, sn.Hex()))
+
+			if err != nil {
+				return fmt.Errorf("could not resolve: %s", err)
+			}
+			var out types.QuerySN
+			if err := json.Unmarshal(res, &out); err != nil {
+				return err
+			}
+			return clientCtx.PrintOutput(out)
+		},
+	}
+}
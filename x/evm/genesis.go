@@ -28,6 +28,37 @@ func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) []abci.ValidatorU
 		}
 	}
 
+	// the heights of the exported chain don't exist on the new one, notes spent
+	// there are recorded as spent at genesis
+	for _, nullifier := range data.Nullifiers {
+		if nullifier.Height > ctx.BlockHeight() {
+			nullifier.Height = ctx.BlockHeight()
+		}
+		k.SetNullifier(ctx, nullifier)
+	}
+
+	for _, tx := range data.SpendingTxs {
+		k.SetSpendingTx(ctx, tx)
+	}
+
+	for _, note := range data.Notes {
+		k.SetNote(ctx, note)
+	}
+
+	for _, cmts := range data.Commitments {
+		if _, err = k.AppendCommitment(ctx, cmts); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, root := range data.CommitmentRoots {
+		k.SetCommitmentRoot(ctx, root)
+	}
+
+	if data.ShieldedSupply != nil {
+		k.SetShieldedSupply(ctx, data.ShieldedSupply)
+	}
+
 	k.SetChainConfig(ctx, data.ChainConfig)
 	k.SetParams(ctx, data.Params)
 
@@ -80,9 +111,15 @@ func ExportGenesis(ctx sdk.Context, k Keeper, ak types.AccountKeeper) GenesisSta
 	config, _ := k.GetChainConfig(ctx)
 
 	return GenesisState{
-		Accounts:    ethGenAccounts,
-		TxsLogs:     k.GetAllTxLogs(ctx),
-		ChainConfig: config,
-		Params:      k.GetParams(ctx),
+		Accounts:        ethGenAccounts,
+		TxsLogs:         k.GetAllTxLogs(ctx),
+		ChainConfig:     config,
+		Params:          k.GetParams(ctx),
+		Nullifiers:      k.GetAllNullifiers(ctx),
+		SpendingTxs:     k.GetAllSpendingTxs(ctx),
+		Notes:           k.GetAllNotes(ctx),
+		ShieldedSupply:  k.GetShieldedSupply(ctx),
+		Commitments:     k.GetAllCommitments(ctx),
+		CommitmentRoots: k.GetAllCommitmentRoots(ctx),
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/cosmos/ethermint/crypto/ethsecp256k1"
 	"github.com/cosmos/ethermint/x/evm"
 	"github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -20,6 +21,29 @@ func (suite *EvmTestSuite) TestExportImport() {
 	_ = evm.InitGenesis(suite.ctx, suite.app.EvmKeeper, genState)
 }
 
+func (suite *EvmTestSuite) TestCommitmentsExportImport() {
+	// the commitments fill a first tree and start a second one
+	for i := 0; i < zktx.MerkleTreeLeaves+3; i++ {
+		_, err := suite.app.EvmKeeper.AppendCommitment(suite.ctx, common.BytesToHash([]byte{byte(i + 1)}))
+		suite.Require().NoError(err)
+	}
+	suite.app.EvmKeeper.UpdateCommitmentRoot(suite.ctx)
+
+	genState := evm.ExportGenesis(suite.ctx, suite.app.EvmKeeper, suite.app.AccountKeeper)
+	suite.Require().NoError(genState.Validate())
+	suite.Require().Len(genState.Commitments, zktx.MerkleTreeLeaves+3)
+	suite.Require().Len(genState.CommitmentRoots, 2)
+
+	suite.SetupTest()
+	_ = evm.InitGenesis(suite.ctx, suite.app.EvmKeeper, genState)
+
+	suite.Require().Equal(genState.Commitments, suite.app.EvmKeeper.GetAllCommitments(suite.ctx))
+	suite.Require().Equal(genState.CommitmentRoots, suite.app.EvmKeeper.GetAllCommitmentRoots(suite.ctx))
+	for _, root := range genState.CommitmentRoots {
+		suite.Require().Equal(root.Root, suite.app.EvmKeeper.GetCommitmentRoot(suite.ctx, root.Size))
+	}
+}
+
 func (suite *EvmTestSuite) TestContractExportImport() {
 	gasLimit := uint64(5000000)
 	gasPrice := big.NewInt(1)
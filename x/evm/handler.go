@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"github.com/cosmos/ethermint/zktx"
 	"github.com/ethereum/go-ethereum/common"
-	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	ethermint "github.com/cosmos/ethermint/types"
@@ -82,65 +81,81 @@ func handleMsgEthereumTx(ctx sdk.Context, k Keeper, msg types.MsgEthereumTx) (*s
 
 
 	//add for blockmaze just like applyTrsaction
-	initSN := zktx.ComputePRF(zktx.ZKTxAddress.Hash().Bytes(), common.Hash{}.Bytes())
-	statedb := k.CommitStateDB
-	if msg.TxCode() == types.MintTx { //
-		if exist := statedb.Exist(common.BytesToAddress(msg.ZKSN().Bytes())); exist == true && (*(msg.ZKSN()) != *(initSN)) { //if sn is already exist,
-			return nil,errors.New("sn is already used")
-		}
-		cmtbalance := k.GetCMTBalance(common.BytesToAddress(msg.From()))
-		if err = zktx.VerifyMintProof(&cmtbalance, msg.ZKSN(), msg.ZKCMT(), msg.ZKValue(), msg.ZKProof()); err != nil {
-			fmt.Println("invalid zk mint proof: ", err)
+	// every account starts from the same initial note, so its SN is never nullified
+	var sn, initSN, cmtOld common.Hash
+	if msg.TxCode() != types.PublicTx {
+		if initSN, _, err = zktx.InitialNote(); err != nil {
 			return nil, err
 		}
-		statedb.CreateAccount(common.BytesToAddress(msg.ZKSN().Bytes()))
-		statedb.SetNonce(common.BytesToAddress(msg.ZKSN().Bytes()), 1)
-	} else if msg.TxCode() == types.SendTx {
-		cmtbalance := k.GetCMTBalance(common.BytesToAddress(msg.From()))
-		if exist := statedb.Exist(common.BytesToAddress(msg.ZKSN().Bytes())); exist == true && (*(msg.ZKSN()) != *(initSN)) { //if sn is already exist,
-			return nil, errors.New("sn is already used ")
+		sn = *msg.ZKSN()
+		if sn != initSN && k.HasNullifier(ctx, sn) {
+			return nil, sdkerrors.Wrapf(types.ErrSNSpent, "sn %s", sn.Hex())
 		}
-		if err = zktx.VerifySendProof(msg.ZKSN(), msg.ZKCMTS(), msg.ZKProof(), &cmtbalance, msg.ZKCMT()); err != nil {
-			fmt.Println("invalid zk send proof: ", err)
-			return nil, err
+		// the proof is verified against the unspent note of the sender it spends
+		if msg.ZKCMTOld() == nil || !k.HasNote(ctx, sender, *msg.ZKCMTOld()) {
+			return nil, sdkerrors.Wrapf(types.ErrUnknownNote, "sender %s", sender.Hex())
 		}
-		statedb.CreateAccount(common.BytesToAddress(msg.ZKSN().Bytes()))
-		statedb.SetNonce(common.BytesToAddress(msg.ZKSN().Bytes()), 1)
-		// } else if tx.TxCode() == types.UpdateTx {
-		// 	cmtbalance := statedb.GetCMTBalance(msg.From())
-		// 	if err = zktx.VerifyUpdateProof(&cmtbalance, tx.RTcmt(), tx.ZKCMT(), tx.ZKProof()); err != nil {
-		// 		fmt.Println("invalid zk update proof: ", err)
-		// 		return nil, 0, err
-		// 	}
-	} else if msg.TxCode() == types.DepositTx {
-		if exist := statedb.Exist(common.BytesToAddress(msg.ZKSN().Bytes())); exist == true && (*(msg.ZKSN()) != *(initSN)) { //if sn is already exist,
-			return nil, errors.New("sn in deposit tx has been already used")
+		cmtOld = *msg.ZKCMTOld()
+	}
+
+	var depositNullifier common.Hash
+	switch data := msg.Shielded.(type) {
+	case nil:
+	case *types.MintTxData, *types.SendTxData, *types.RedeemTxData:
+	case *types.DepositTxData:
+		if !k.HasCommitmentRoot(ctx, data.RTcmt) {
+			return nil, sdkerrors.Wrapf(types.ErrUnknownCommitmentRoot, "root %s", data.RTcmt.Hex())
 		}
-		cmtbalance := k.GetCMTBalance(common.BytesToAddress(msg.From()))
-		addr1, err := types.ExtractPKBAddress(ethtypes.HomesteadSigner{}, &msg) //tbd
-		ppp := ecdsa.PublicKey{crypto.S256(), msg.X(), msg.Y()}
+		addr1, err := msg.VerifyDepositSig(chainIDEpoch)
+		ppp := ecdsa.PublicKey{Curve: crypto.S256(), X: data.X, Y: data.Y}
 		addr2 := crypto.PubkeyToAddress(ppp)
 		if err != nil || addr1 != addr2 {
 			return nil, errors.New("invalid depositTx signature ")
 		}
-		if err = zktx.VerifyDepositProof(&ppp, msg.RTcmt(), &cmtbalance, msg.ZKSN(), msg.ZKCMT(), msg.ZKSNS(), msg.ZKProof()); err != nil {
-			fmt.Println("invalid zk deposit proof: ", err)
-			return nil,  err
+		depositNullifier = types.DepositNullifier(&ppp)
+		if k.HasNullifier(ctx, depositNullifier) {
+			return nil, sdkerrors.Wrapf(types.ErrNoteDeposited, "one-time key %s", addr2.Hex())
 		}
-		statedb.CreateAccount(common.BytesToAddress(msg.ZKSN().Bytes()))
-		statedb.SetNonce(common.BytesToAddress(msg.ZKSN().Bytes()), 1)
-	} else if msg.TxCode() == types.RedeemTx {
-		if exist := statedb.Exist(common.BytesToAddress(msg.ZKSN().Bytes())); exist == true && (*(msg.ZKSN()) != *(initSN)) { //if sn is already exist,
-			return nil, errors.New("sn is already used ")
+	default:
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unsupported shielded transaction type %T", data)
+	}
+
+	if msg.TxCode() != types.PublicTx {
+		// the proof has been verified by the ante handler, this hits its cache
+		if err = k.VerifyProof(ctx, msg, st.Simulate); err != nil {
+			k.Logger(ctx).Info("invalid zk proof", "code", msg.TxCode(), "error", err)
+			return nil, err
 		}
-		cmtbalance := k.GetCMTBalance(common.BytesToAddress(msg.From()))
-		if err = zktx.VerifyRedeemProof(&cmtbalance, msg.ZKSN(), msg.ZKCMT(), msg.ZKValue(), msg.ZKProof()); err != nil {
-			fmt.Println("invalid zk redeem proof: ", err)
+	}
+
+	var nullifiers uint32
+	if sn != initSN {
+		k.SetNullifier(ctx, types.NewNullifier(sn, ctx.BlockHeight(), ethHash))
+		nullifiers++
+	}
+	if depositNullifier != (common.Hash{}) {
+		k.SetNullifier(ctx, types.NewNullifier(depositNullifier, ctx.BlockHeight(), ethHash))
+		nullifiers++
+	}
+	if nullifiers > 0 {
+		k.SetSpendingTx(ctx, types.NewSpendingTx(ethHash, msg.TxCode(), nullifiers))
+	}
+	if send, ok := msg.Shielded.(*types.SendTxData); ok {
+		if _, err = k.AppendCommitment(ctx, send.CMTS); err != nil {
 			return nil, err
 		}
-		statedb.CreateAccount(common.BytesToAddress(msg.ZKSN().Bytes()))
-		statedb.SetNonce(common.BytesToAddress(msg.ZKSN().Bytes()), 1)
+		k.SetCommitmentTxHash(ctx, send.CMTS, ethHash)
+	}
+	if msg.TxCode() != types.PublicTx {
+		// the spent note is replaced by the created one in the unspent notes of
+		// the sender, the initial note is never stored
+		k.DeleteNote(ctx, sender, cmtOld)
+		k.SetNote(ctx, types.NewNote(sender, *msg.ZKCMT()))
+
+		st.TxCode = msg.TxCode()
+		st.ZKValue = msg.ZKValue()
 	}
+
 	executionResult, err := st.TransitionDb(ctx, config)
 	if err != nil {
 		return nil, err
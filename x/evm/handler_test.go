@@ -2,6 +2,7 @@ package evm_test
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -25,6 +26,7 @@ import (
 	"github.com/cosmos/ethermint/x/evm"
 	"github.com/cosmos/ethermint/x/evm/keeper"
 	"github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
@@ -415,3 +417,80 @@ func (suite *EvmTestSuite) TestSendTransaction() {
 	suite.Require().NoError(err)
 	suite.Require().NotNil(result)
 }
+
+func (suite *EvmTestSuite) TestHandleDepositTx() {
+	initialSN, initialCMT := ethcmn.BytesToHash([]byte("initial sn")), ethcmn.BytesToHash([]byte("initial cmt"))
+	zktx.SetInitialNote(initialSN, initialCMT)
+
+	testCases := []struct {
+		msg    string
+		root   func(leaves []*ethcmn.Hash) ethcmn.Hash
+		expErr error
+	}{
+		{"final root of a full tree", func(leaves []*ethcmn.Hash) ethcmn.Hash {
+			return zktx.GenRT(leaves[:zktx.MerkleTreeLeaves])
+		}, nil},
+		{"accepted root", func(leaves []*ethcmn.Hash) ethcmn.Hash {
+			// the root recorded by the keeper is the one rebuilt by the circuit
+			latest, found := suite.app.EvmKeeper.GetLatestCommitmentRoot(suite.ctx)
+			suite.Require().True(found)
+			suite.Require().Equal(zktx.GenRT(leaves[zktx.MerkleTreeLeaves:zktx.MerkleTreeLeaves+1]), latest.Root)
+			return latest.Root
+		}, nil},
+		{"root not recorded yet", func(leaves []*ethcmn.Hash) ethcmn.Hash {
+			return zktx.GenRT(leaves[zktx.MerkleTreeLeaves:])
+		}, types.ErrUnknownCommitmentRoot},
+		{"unknown root", func([]*ethcmn.Hash) ethcmn.Hash {
+			return ethcmn.BytesToHash([]byte("unknown root"))
+		}, types.ErrUnknownCommitmentRoot},
+	}
+
+	for _, tc := range testCases {
+		suite.SetupTest()
+		suite.app.EvmKeeper.Verifier = app.AcceptVerifier{}
+		suite.handler = evm.NewHandler(suite.app.EvmKeeper)
+
+		// a first block fills the first tree, starts the second one and records
+		// their roots, the send claimed by the deposit is appended in the next one
+		var leaves []*ethcmn.Hash
+		for i := 0; i < zktx.MerkleTreeLeaves+2; i++ {
+			cmts := ethcmn.BytesToHash([]byte{byte(i + 1)})
+			ctx := suite.ctx.WithBlockHeight(1)
+			if i == zktx.MerkleTreeLeaves+1 {
+				suite.app.EvmKeeper.UpdateCommitmentRoot(ctx)
+				ctx = suite.ctx.WithBlockHeight(2)
+			}
+			_, err := suite.app.EvmKeeper.AppendCommitment(ctx, cmts)
+			suite.Require().NoError(err)
+			leaves = append(leaves, &cmts)
+		}
+
+		priv, err := ethsecp256k1.GenerateKey()
+		suite.Require().NoError(err)
+		otk, err := ethcrypto.GenerateKey()
+		suite.Require().NoError(err)
+
+		cmt, sns := ethcmn.BytesToHash([]byte("cmt")), ethcmn.BytesToHash([]byte("sns"))
+		msg := types.NewMsgEthereumTx(0, &zktx.ZKTxAddress, big.NewInt(0), 300000, big.NewInt(0), nil)
+		msg.SetTxCode(types.DepositTx)
+		msg.SetZKSN(&initialSN)
+		msg.SetZKCMTOld(&initialCMT)
+		msg.SetZKCMT(&cmt)
+		msg.SetZKSNS(&sns)
+		msg.SetRTcmt(tc.root(leaves))
+		msg.SetPubKey(otk.X, otk.Y)
+		msg.SetZKProof([]byte("proof"))
+		suite.Require().NoError(msg.Sign(big.NewInt(3), priv.ToECDSA()))
+		suite.Require().NoError(msg.SignDeposit(big.NewInt(3), otk))
+
+		_, err = suite.handler(suite.ctx.WithTxBytes([]byte(tc.msg)), msg)
+		depositNullifier := types.DepositNullifier(&otk.PublicKey)
+		if tc.expErr != nil {
+			suite.Require().True(errors.Is(err, tc.expErr), "%s: %v", tc.msg, err)
+			suite.Require().False(suite.app.EvmKeeper.HasNullifier(suite.ctx, depositNullifier), tc.msg)
+		} else {
+			suite.Require().NoError(err, tc.msg)
+			suite.Require().True(suite.app.EvmKeeper.HasNullifier(suite.ctx, depositNullifier), tc.msg)
+		}
+	}
+}
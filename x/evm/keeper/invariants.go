@@ -0,0 +1,111 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RegisterInvariants registers the evm module invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper, ak types.AccountKeeper, sk types.SupplyKeeper) {
+	ir.RegisterRoute(types.ModuleName, "shielded-supply", ShieldedSupplyInvariant(k, ak, sk))
+	ir.RegisterRoute(types.ModuleName, "nullifiers", NullifiersInvariant(k))
+}
+
+// AllInvariants runs all invariants of the evm module.
+func AllInvariants(k Keeper, ak types.AccountKeeper, sk types.SupplyKeeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		res, stop := ShieldedSupplyInvariant(k, ak, sk)(ctx)
+		if stop {
+			return res, stop
+		}
+		return NullifiersInvariant(k)(ctx)
+	}
+}
+
+// ShieldedSupplyInvariant checks that the public balances of the EVM denomination
+// plus the shielded supply add up to the total supply, and that the shielded
+// supply is held by the shielded pool account.
+func ShieldedSupplyInvariant(k Keeper, ak types.AccountKeeper, sk types.SupplyKeeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		evmDenom := k.GetParams(ctx).EvmDenom
+
+		public := sdk.ZeroInt()
+		pool := sdk.ZeroInt()
+		ak.IterateAccounts(ctx, func(acc authexported.Account) bool {
+			if acc.GetAddress().Equals(types.ShieldedPoolAddress) {
+				pool = acc.GetCoins().AmountOf(evmDenom)
+				return false
+			}
+			public = public.Add(acc.GetCoins().AmountOf(evmDenom))
+			return false
+		})
+
+		shielded := sdk.NewIntFromBigInt(k.GetShieldedSupply(ctx))
+		total := sk.GetSupply(ctx).GetTotal().AmountOf(evmDenom)
+
+		broken := !public.Add(shielded).Equal(total) || !pool.Equal(shielded)
+
+		return sdk.FormatInvariant(types.ModuleName, "shielded supply",
+			fmt.Sprintf(
+				"\tpublic balances:       %s%s\n"+
+					"\tshielded supply:       %s%s\n"+
+					"\tshielded pool balance: %s%s\n"+
+					"\tsupply.Total:          %s%s\n",
+				public, evmDenom, shielded, evmDenom, pool, evmDenom, total, evmDenom)), broken
+	}
+}
+
+// NullifiersInvariant checks that every entry of the nullifier set has been added
+// at or before the current height by a processed shielded transaction, i.e. one
+// whose logs are in the store and that has been recorded as spending, and that
+// every spending transaction added as many entries as it recorded.
+func NullifiersInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+
+		spent := make(map[common.Hash]uint32)
+		k.IterateNullifiers(ctx, func(nullifier types.Nullifier) bool {
+			spent[nullifier.TxHash]++
+
+			switch tx, found := k.GetSpendingTx(ctx, nullifier.TxHash); {
+			case nullifier.Height > ctx.BlockHeight():
+				count++
+				msg += fmt.Sprintf("\tserial number %s spent at future height %d\n",
+					nullifier.SN.Hex(), nullifier.Height)
+			case !k.HasTxLogs(ctx, nullifier.TxHash):
+				count++
+				msg += fmt.Sprintf("\tserial number %s spent at height %d by unknown transaction %s\n",
+					nullifier.SN.Hex(), nullifier.Height, nullifier.TxHash.Hex())
+			case !found:
+				count++
+				msg += fmt.Sprintf("\tserial number %s spent by transaction %s not recorded as spending\n",
+					nullifier.SN.Hex(), nullifier.TxHash.Hex())
+			case tx.Validate() != nil:
+				count++
+				msg += fmt.Sprintf("\tserial number %s spent by invalid spending transaction %s: %s\n",
+					nullifier.SN.Hex(), nullifier.TxHash.Hex(), tx.Validate())
+			}
+			return false
+		})
+
+		k.IterateSpendingTxs(ctx, func(tx types.SpendingTx) bool {
+			if spent[tx.TxHash] != tx.Nullifiers {
+				count++
+				msg += fmt.Sprintf("\ttransaction %s spent %d serial numbers, %d in the nullifier set\n",
+					tx.TxHash.Hex(), tx.Nullifiers, spent[tx.TxHash])
+			}
+			return false
+		})
+		broken := count != 0
+
+		return sdk.FormatInvariant(types.ModuleName, "nullifiers",
+			fmt.Sprintf("amount of inconsistent nullifiers found %d\n%s", count, msg)), broken
+	}
+}
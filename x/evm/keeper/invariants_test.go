@@ -0,0 +1,88 @@
+package keeper_test
+
+import (
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+
+	ethermint "github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/evm/keeper"
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+func (suite *KeeperTestSuite) TestShieldedSupplyInvariant() {
+	invariant := keeper.ShieldedSupplyInvariant(suite.app.EvmKeeper, suite.app.AccountKeeper, suite.app.SupplyKeeper)
+
+	addr := sdk.AccAddress(suite.address.Bytes())
+	acc := suite.app.AccountKeeper.GetAccount(suite.ctx, addr)
+	suite.Require().NoError(acc.SetCoins(sdk.NewCoins(ethermint.NewPhotonCoin(sdk.NewInt(1000)))))
+	suite.app.AccountKeeper.SetAccount(suite.ctx, acc)
+
+	total := suite.app.SupplyKeeper.GetSupply(suite.ctx).GetTotal()
+	suite.app.SupplyKeeper.SetSupply(suite.ctx, supply.NewSupply(total.Add(ethermint.NewPhotonCoin(sdk.NewInt(1000)))))
+
+	_, broken := invariant(suite.ctx)
+	suite.Require().False(broken)
+
+	// mint 400 into the shielded pool
+	acc = suite.app.AccountKeeper.GetAccount(suite.ctx, addr)
+	suite.Require().NoError(acc.SetCoins(sdk.NewCoins(ethermint.NewPhotonCoin(sdk.NewInt(600)))))
+	suite.app.AccountKeeper.SetAccount(suite.ctx, acc)
+	csdb := suite.app.EvmKeeper.CommitStateDB.WithContext(suite.ctx)
+	suite.Require().NoError(csdb.AddShieldedSupply(big.NewInt(400)))
+	suite.Require().NoError(csdb.Finalise(false))
+
+	_, broken = invariant(suite.ctx)
+	suite.Require().False(broken)
+
+	// value created inside the shielded pool
+	suite.app.EvmKeeper.SetShieldedSupply(suite.ctx, big.NewInt(500))
+
+	_, broken = invariant(suite.ctx)
+	suite.Require().True(broken)
+}
+
+func (suite *KeeperTestSuite) TestNullifiersInvariant() {
+	invariant := keeper.NullifiersInvariant(suite.app.EvmKeeper)
+	ctx := suite.ctx.WithBlockHeight(10)
+
+	txHash := ethcmn.BytesToHash(hash)
+	sn, sns := ethcmn.BytesToHash([]byte("sn")), ethcmn.BytesToHash([]byte("sns"))
+	suite.app.EvmKeeper.SetNullifier(ctx, types.NewNullifier(sn, 5, txHash))
+
+	// spent by an unknown transaction
+	_, broken := invariant(ctx)
+	suite.Require().True(broken)
+
+	// spent by a transaction not recorded as spending
+	suite.Require().NoError(suite.app.EvmKeeper.SetLogs(ctx, txHash, []*ethtypes.Log{}))
+	_, broken = invariant(ctx)
+	suite.Require().True(broken)
+
+	suite.app.EvmKeeper.SetSpendingTx(ctx, types.NewSpendingTx(txHash, types.SendTx, 1))
+	_, broken = invariant(ctx)
+	suite.Require().False(broken)
+
+	// spent by a public transaction
+	suite.app.EvmKeeper.SetSpendingTx(ctx, types.NewSpendingTx(txHash, types.PublicTx, 1))
+	_, broken = invariant(ctx)
+	suite.Require().True(broken)
+
+	// a deposit adds two nullifiers
+	suite.app.EvmKeeper.SetSpendingTx(ctx, types.NewSpendingTx(txHash, types.DepositTx, 2))
+	_, broken = invariant(ctx)
+	suite.Require().True(broken)
+
+	suite.app.EvmKeeper.SetNullifier(ctx, types.NewNullifier(sns, 5, txHash))
+	_, broken = invariant(ctx)
+	suite.Require().False(broken)
+
+	// spent in the future
+	suite.app.EvmKeeper.SetNullifier(ctx, types.NewNullifier(sns, 11, txHash))
+	_, broken = invariant(ctx)
+	suite.Require().True(broken)
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/big"
-	"sync"
 
 	"github.com/tendermint/tendermint/libs/log"
 
@@ -14,6 +13,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/params"
 
 	"github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
 
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -38,11 +38,19 @@ type Keeper struct {
 	// on the KVStore or adding it as a field on the EVM genesis state.
 	TxCount int
 	Bloom   *big.Int
+	// Verifier checks the zk-SNARK proofs of shielded transactions
+	Verifier zktx.Verifier
+	// Transactions whose proof has been verified, shared between the CheckTx and
+	// DeliverTx states. It is a cache, not part of the consensus state.
+	proofs *proofCache
+	// SNs spent by the transactions accepted at CheckTx, see CheckSN
+	pendingSNs *pendingSNSet
 }
 
 // NewKeeper generates new evm module keeper
-func newKeeper(
+func NewKeeper(
 	cdc *codec.Codec, storeKey sdk.StoreKey, paramSpace params.Subspace, ak types.AccountKeeper,
+	verifier zktx.Verifier,
 ) Keeper {
 	// set KeyTable if it has not already been set
 	if !paramSpace.HasKeyTable() {
@@ -56,21 +64,12 @@ func newKeeper(
 		CommitStateDB: types.NewCommitStateDB(sdk.Context{}, storeKey, paramSpace, ak),
 		TxCount:       0,
 		Bloom:         big.NewInt(0),
+		Verifier:      verifier,
+		proofs:        newProofCache(proofCacheSize),
+		pendingSNs:    newPendingSNSet(),
 	}
 }
 
-var once sync.Once
-var keeper Keeper
-// NewKeeper generates new evm module keeper
-func NewKeeper(
-	cdc *codec.Codec, storeKey sdk.StoreKey, paramSpace params.Subspace, ak types.AccountKeeper,
-) Keeper {
-	once.Do(func() {
-		keeper = newKeeper(cdc, storeKey, paramSpace, ak)
-	})
-	return keeper
-}
-
 // Logger returns a module-specific logger.
 func (k Keeper) Logger(ctx sdk.Context) log.Logger {
 	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
@@ -143,6 +142,13 @@ func (k Keeper) GetAllTxLogs(ctx sdk.Context) []types.TransactionLogs {
 	return txsLogs
 }
 
+// HasTxLogs returns true if the logs of the transaction with the given hash
+// are in the store, which is the case for every processed transaction.
+func (k Keeper) HasTxLogs(ctx sdk.Context, hash common.Hash) bool {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixLogs)
+	return store.Has(hash.Bytes())
+}
+
 // GetAccountStorage return state storage associated with an account
 func (k Keeper) GetAccountStorage(ctx sdk.Context, address common.Address) (types.Storage, error) {
 	storage := types.Storage{}
@@ -180,6 +186,175 @@ func (k Keeper) SetChainConfig(ctx sdk.Context, config types.ChainConfig) {
 }
 
 
+// ----------------------------------------------------------------------------
+// Nullifier set
+// Serial numbers (SN) of the spent shielded notes.
+// ----------------------------------------------------------------------------
+
+// HasNullifier returns true if the note with the given serial number has been spent
+func (k Keeper) HasNullifier(ctx sdk.Context, sn common.Hash) bool {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixNullifier)
+	return store.Has(sn.Bytes())
+}
+
+// GetNullifier returns the height at which the note with the given serial number
+// has been spent, or false if it hasn't been spent
+func (k Keeper) GetNullifier(ctx sdk.Context, sn common.Hash) (int64, bool) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixNullifier)
+	bz := store.Get(sn.Bytes())
+	if len(bz) == 0 {
+		return 0, false
+	}
+
+	return types.DecodeNullifier(sn, bz).Height, true
+}
+
+// SetNullifier marks the note with the given serial number as spent, storing
+// the height and the hash of the transaction that spent it
+func (k Keeper) SetNullifier(ctx sdk.Context, nullifier types.Nullifier) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixNullifier)
+	store.Set(nullifier.SN.Bytes(), nullifier.Encode())
+}
+
+// IterateNullifiers iterates over the nullifier set and performs a callback
+// function with each spent serial number. The iteration stops when the callback
+// returns true.
+func (k Keeper) IterateNullifiers(ctx sdk.Context, cb func(nullifier types.Nullifier) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.KeyPrefixNullifier)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		sn := common.BytesToHash(iterator.Key()[len(types.KeyPrefixNullifier):])
+		if cb(types.DecodeNullifier(sn, iterator.Value())) {
+			break
+		}
+	}
+}
+
+// GetAllNullifiers returns all the spent serial numbers from the store.
+func (k Keeper) GetAllNullifiers(ctx sdk.Context) []types.Nullifier {
+	nullifiers := []types.Nullifier{}
+	k.IterateNullifiers(ctx, func(nullifier types.Nullifier) bool {
+		nullifiers = append(nullifiers, nullifier)
+		return false
+	})
+	return nullifiers
+}
+
+// GetSpendingTx returns the shielded transaction with the given hash that added
+// entries to the nullifier set, or false if it isn't known
+func (k Keeper) GetSpendingTx(ctx sdk.Context, txHash common.Hash) (types.SpendingTx, bool) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixSpendingTx)
+	bz := store.Get(txHash.Bytes())
+	if len(bz) == 0 {
+		return types.SpendingTx{}, false
+	}
+
+	return types.DecodeSpendingTx(txHash, bz), true
+}
+
+// SetSpendingTx records a shielded transaction that added entries to the
+// nullifier set
+func (k Keeper) SetSpendingTx(ctx sdk.Context, tx types.SpendingTx) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixSpendingTx)
+	store.Set(tx.TxHash.Bytes(), tx.Encode())
+}
+
+// IterateSpendingTxs iterates over the recorded spending transactions and
+// performs a callback function with each of them. The iteration stops when the
+// callback returns true.
+func (k Keeper) IterateSpendingTxs(ctx sdk.Context, cb func(tx types.SpendingTx) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.KeyPrefixSpendingTx)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		txHash := common.BytesToHash(iterator.Key()[len(types.KeyPrefixSpendingTx):])
+		if cb(types.DecodeSpendingTx(txHash, iterator.Value())) {
+			break
+		}
+	}
+}
+
+// GetAllSpendingTxs returns all the recorded spending transactions from the store.
+func (k Keeper) GetAllSpendingTxs(ctx sdk.Context) []types.SpendingTx {
+	txs := []types.SpendingTx{}
+	k.IterateSpendingTxs(ctx, func(tx types.SpendingTx) bool {
+		txs = append(txs, tx)
+		return false
+	})
+	return txs
+}
+
+// ----------------------------------------------------------------------------
+// Note set
+// Commitments of the unspent shielded notes of each account.
+// ----------------------------------------------------------------------------
+
+// HasNote returns true if the note with the given commitment is an unspent note
+// of the owner. Every account holds the zero valued initial note, which can be
+// spent any number of times, once it is known.
+func (k Keeper) HasNote(ctx sdk.Context, owner common.Address, cmt common.Hash) bool {
+	if _, initialCMT, err := zktx.InitialNote(); err == nil && cmt == initialCMT {
+		return true
+	}
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.AddressNotePrefix(owner))
+	return store.Has(cmt.Bytes())
+}
+
+// SetNote adds a note to the unspent notes of its owner
+func (k Keeper) SetNote(ctx sdk.Context, note types.Note) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.AddressNotePrefix(note.Owner))
+	store.Set(note.CMT.Bytes(), []byte{1})
+}
+
+// DeleteNote removes a spent note from the unspent notes of the owner
+func (k Keeper) DeleteNote(ctx sdk.Context, owner common.Address, cmt common.Hash) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.AddressNotePrefix(owner))
+	store.Delete(cmt.Bytes())
+}
+
+// GetNotes returns the commitments of the unspent notes of the owner, without
+// the initial note.
+func (k Keeper) GetNotes(ctx sdk.Context, owner common.Address) []common.Hash {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.AddressNotePrefix(owner))
+	defer iterator.Close()
+
+	cmts := []common.Hash{}
+	for ; iterator.Valid(); iterator.Next() {
+		cmts = append(cmts, common.BytesToHash(iterator.Key()[len(types.KeyPrefixNote)+common.AddressLength:]))
+	}
+	return cmts
+}
+
+// IterateNotes iterates over the unspent notes of all the accounts and performs
+// a callback function with each of them. The iteration stops when the callback
+// returns true.
+func (k Keeper) IterateNotes(ctx sdk.Context, cb func(note types.Note) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.KeyPrefixNote)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		key := iterator.Key()[len(types.KeyPrefixNote):]
+		note := types.NewNote(common.BytesToAddress(key[:common.AddressLength]), common.BytesToHash(key[common.AddressLength:]))
+		if cb(note) {
+			break
+		}
+	}
+}
+
+// GetAllNotes returns the unspent notes of all the accounts from the store.
+func (k Keeper) GetAllNotes(ctx sdk.Context) []types.Note {
+	notes := []types.Note{}
+	k.IterateNotes(ctx, func(note types.Note) bool {
+		notes = append(notes, note)
+		return false
+	})
+	return notes
+}
 
 ///
 func (k Keeper) GetCommitStateDB() *types.CommitStateDB {
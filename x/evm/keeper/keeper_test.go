@@ -14,6 +14,7 @@ import (
 	ethermint "github.com/cosmos/ethermint/types"
 	"github.com/cosmos/ethermint/x/evm/keeper"
 	"github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -161,3 +162,42 @@ func (suite *KeeperTestSuite) TestChainConfig() {
 	suite.Require().True(found)
 	suite.Require().Equal(config, newConfig)
 }
+
+func (suite *KeeperTestSuite) TestNullifiers() {
+	sn := ethcmn.BytesToHash([]byte("sn"))
+	suite.Require().False(suite.app.EvmKeeper.HasNullifier(suite.ctx, sn))
+
+	nullifier := types.NewNullifier(sn, 10, ethcmn.BytesToHash(hash))
+	suite.app.EvmKeeper.SetNullifier(suite.ctx, nullifier)
+	suite.Require().True(suite.app.EvmKeeper.HasNullifier(suite.ctx, sn))
+
+	height, found := suite.app.EvmKeeper.GetNullifier(suite.ctx, sn)
+	suite.Require().True(found)
+	suite.Require().Equal(int64(10), height)
+	suite.Require().Equal([]types.Nullifier{nullifier}, suite.app.EvmKeeper.GetAllNullifiers(suite.ctx))
+}
+
+func (suite *KeeperTestSuite) TestNotes() {
+	initialCMT := ethcmn.BytesToHash([]byte("initial cmt"))
+	zktx.SetInitialNote(ethcmn.BytesToHash([]byte("initial sn")), initialCMT)
+
+	owner, other := ethcmn.BytesToAddress([]byte("owner")), ethcmn.BytesToAddress([]byte("other"))
+	cmt1, cmt2 := ethcmn.BytesToHash([]byte("cmt1")), ethcmn.BytesToHash([]byte("cmt2"))
+
+	// every account holds the initial note
+	suite.Require().True(suite.app.EvmKeeper.HasNote(suite.ctx, owner, initialCMT))
+	suite.Require().False(suite.app.EvmKeeper.HasNote(suite.ctx, owner, cmt1))
+	suite.Require().Empty(suite.app.EvmKeeper.GetNotes(suite.ctx, owner))
+
+	suite.app.EvmKeeper.SetNote(suite.ctx, types.NewNote(owner, cmt1))
+	suite.app.EvmKeeper.SetNote(suite.ctx, types.NewNote(owner, cmt2))
+	suite.app.EvmKeeper.SetNote(suite.ctx, types.NewNote(other, cmt1))
+	suite.Require().True(suite.app.EvmKeeper.HasNote(suite.ctx, owner, cmt1))
+	suite.Require().Equal([]ethcmn.Hash{cmt1, cmt2}, suite.app.EvmKeeper.GetNotes(suite.ctx, owner))
+	suite.Require().Len(suite.app.EvmKeeper.GetAllNotes(suite.ctx), 3)
+
+	suite.app.EvmKeeper.DeleteNote(suite.ctx, owner, cmt1)
+	suite.Require().False(suite.app.EvmKeeper.HasNote(suite.ctx, owner, cmt1))
+	suite.Require().True(suite.app.EvmKeeper.HasNote(suite.ctx, other, cmt1))
+	suite.Require().Equal([]ethcmn.Hash{cmt2}, suite.app.EvmKeeper.GetNotes(suite.ctx, owner))
+}
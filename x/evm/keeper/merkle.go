@@ -0,0 +1,265 @@
+package keeper
+
+import (
+	"encoding/binary"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ----------------------------------------------------------------------------
+// Commitment tree
+// Append-only sequence of Merkle trees of zktx.MerkleTreeLeaves send
+// commitments (CMTS), the largest tree the deposit circuit hashes. Once a tree
+// is full the next commitments go to a new one. Leaves are numbered across the
+// trees, the tree of a leaf being its index shifted by zktx.MerkleTreeDepth, and
+// the node at height zktx.MerkleTreeDepth is the root of a tree. Only the nodes
+// of complete subtrees are stored, so that the trees can be rebuilt at any size.
+// ----------------------------------------------------------------------------
+
+// GetCommitmentTreeSize returns the number of commitments in all the trees
+func (k Keeper) GetCommitmentTreeSize(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.KeyPrefixTreeSize)
+	if len(bz) == 0 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(bz)
+}
+
+// GetCommitmentIndex returns the leaf index of the given send commitment across
+// the trees, or false if it isn't in any tree
+func (k Keeper) GetCommitmentIndex(ctx sdk.Context, cmts common.Hash) (uint64, bool) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixCommitment)
+	bz := store.Get(cmts.Bytes())
+	if len(bz) == 0 {
+		return 0, false
+	}
+
+	return binary.BigEndian.Uint64(bz), true
+}
+
+// AppendCommitment appends a send commitment to the current tree, or to a new
+// one if it is full, and returns its leaf index across the trees
+func (k Keeper) AppendCommitment(ctx sdk.Context, cmts common.Hash) (uint64, error) {
+	if _, found := k.GetCommitmentIndex(ctx, cmts); found {
+		return 0, types.ErrDuplicateCommitment
+	}
+
+	index := k.GetCommitmentTreeSize(ctx)
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixCommitment)
+	store.Set(cmts.Bytes(), sdk.Uint64ToBigEndian(index))
+
+	// store the leaf and every subtree of its tree it completes
+	node := cmts
+	i := index
+	k.setMerkleNode(ctx, 0, i, node)
+	for height := 0; height < zktx.MerkleTreeDepth && i&1 == 1; height++ {
+		node = zktx.MerkleHash(k.getMerkleNode(ctx, height, i-1), node)
+		i >>= 1
+		k.setMerkleNode(ctx, height+1, i, node)
+	}
+
+	ctx.KVStore(k.storeKey).Set(types.KeyPrefixTreeSize, sdk.Uint64ToBigEndian(index+1))
+	return index, nil
+}
+
+// GetCommitmentTxHash returns the hash of the send transaction that created the
+// given send commitment, or false if it isn't known. Commitments imported from
+// genesis have no transaction.
+func (k Keeper) GetCommitmentTxHash(ctx sdk.Context, cmts common.Hash) (common.Hash, bool) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixCommitmentTx)
+	bz := store.Get(cmts.Bytes())
+	if len(bz) == 0 {
+		return common.Hash{}, false
+	}
+
+	return common.BytesToHash(bz), true
+}
+
+// SetCommitmentTxHash records the hash of the send transaction that created the
+// given send commitment
+func (k Keeper) SetCommitmentTxHash(ctx sdk.Context, cmts, txHash common.Hash) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixCommitmentTx)
+	store.Set(cmts.Bytes(), txHash.Bytes())
+}
+
+// GetCommitmentRoot returns the root of the last tree once the first size
+// commitments have been appended, i.e. of the tree holding the commitment
+// size-1
+func (k Keeper) GetCommitmentRoot(ctx sdk.Context, size uint64) common.Hash {
+	if size == 0 {
+		return zktx.EmptyMerkleNode(zktx.MerkleTreeDepth)
+	}
+	return k.merkleNodeAt(ctx, zktx.MerkleTreeDepth, (size-1)>>zktx.MerkleTreeDepth, size)
+}
+
+// GetCommitmentPath returns the authentication path of the leaf at the given
+// index in its tree once the first size commitments have been appended, leaf
+// level first
+func (k Keeper) GetCommitmentPath(ctx sdk.Context, index, size uint64) []common.Hash {
+	path := make([]common.Hash, zktx.MerkleTreeDepth)
+	for height := range path {
+		path[height] = k.merkleNodeAt(ctx, height, (index>>uint(height))^1, size)
+	}
+	return path
+}
+
+// merkleNodeAt returns the node at the given height and index once the first
+// size commitments have been appended.
+func (k Keeper) merkleNodeAt(ctx sdk.Context, height int, index, size uint64) common.Hash {
+	start, end := index<<uint(height), (index+1)<<uint(height)
+	switch {
+	case end <= size:
+		return k.getMerkleNode(ctx, height, index)
+	case start >= size:
+		return zktx.EmptyMerkleNode(height)
+	default:
+		return zktx.MerkleHash(
+			k.merkleNodeAt(ctx, height-1, 2*index, size),
+			k.merkleNodeAt(ctx, height-1, 2*index+1, size),
+		)
+	}
+}
+
+func (k Keeper) getMerkleNode(ctx sdk.Context, height int, index uint64) common.Hash {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixMerkleNode)
+	return common.BytesToHash(store.Get(types.MerkleNodeKey(height, index)))
+}
+
+func (k Keeper) setMerkleNode(ctx sdk.Context, height int, index uint64, node common.Hash) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixMerkleNode)
+	store.Set(types.MerkleNodeKey(height, index), node.Bytes())
+}
+
+// HasCommitmentRoot returns true if the given root is one of the recorded roots
+// of the trees
+func (k Keeper) HasCommitmentRoot(ctx sdk.Context, root common.Hash) bool {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixCommitmentRoot)
+	return store.Has(root.Bytes())
+}
+
+// SetCommitmentRoot records a root of the trees. The final roots of the full
+// trees are kept, while only the CommitmentRootHistory most recent roots of
+// partial trees are, the older ones being dropped.
+func (k Keeper) SetCommitmentRoot(ctx sdk.Context, root types.CommitmentRoot) {
+	store := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixCommitmentRoot)
+	store.Set(root.Root.Bytes(), sdk.Uint64ToBigEndian(root.Size))
+
+	history := prefix.NewStore(ctx.KVStore(k.storeKey), types.KeyPrefixRootHistory)
+	history.Set(sdk.Uint64ToBigEndian(root.Size), root.Encode())
+
+	var partial []types.CommitmentRoot
+	k.IterateCommitmentRoots(ctx, func(root types.CommitmentRoot) bool {
+		if !root.IsFull() {
+			partial = append(partial, root)
+		}
+		return false
+	})
+
+	for i := 0; i < len(partial)-types.CommitmentRootHistory; i++ {
+		store.Delete(partial[i].Root.Bytes())
+		history.Delete(sdk.Uint64ToBigEndian(partial[i].Size))
+	}
+}
+
+// GetLatestCommitmentRoot returns the most recently recorded root, or false if
+// no root has been recorded
+func (k Keeper) GetLatestCommitmentRoot(ctx sdk.Context) (types.CommitmentRoot, bool) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStoreReversePrefixIterator(store, types.KeyPrefixRootHistory)
+	defer iterator.Close()
+
+	if !iterator.Valid() {
+		return types.CommitmentRoot{}, false
+	}
+
+	size := binary.BigEndian.Uint64(iterator.Key()[len(types.KeyPrefixRootHistory):])
+	return types.DecodeCommitmentRoot(size, iterator.Value()), true
+}
+
+// IterateCommitmentRoots iterates over the recorded roots of the trees, oldest
+// first, and performs a callback function with each of them. The iteration
+// stops when the callback returns true.
+func (k Keeper) IterateCommitmentRoots(ctx sdk.Context, cb func(root types.CommitmentRoot) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.KeyPrefixRootHistory)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		size := binary.BigEndian.Uint64(iterator.Key()[len(types.KeyPrefixRootHistory):])
+		if cb(types.DecodeCommitmentRoot(size, iterator.Value())) {
+			break
+		}
+	}
+}
+
+// GetAllCommitmentRoots returns the recorded roots of the trees, oldest first.
+func (k Keeper) GetAllCommitmentRoots(ctx sdk.Context) []types.CommitmentRoot {
+	roots := []types.CommitmentRoot{}
+	k.IterateCommitmentRoots(ctx, func(root types.CommitmentRoot) bool {
+		roots = append(roots, root)
+		return false
+	})
+	return roots
+}
+
+// GetAllCommitments returns the send commitments of all the trees in leaf
+// order.
+func (k Keeper) GetAllCommitments(ctx sdk.Context) []common.Hash {
+	return k.getCommitments(ctx, 0, k.GetCommitmentTreeSize(ctx))
+}
+
+// GetTreeCommitments returns the send commitments of the given tree in leaf
+// order.
+func (k Keeper) GetTreeCommitments(ctx sdk.Context, tree uint64) []common.Hash {
+	start := tree << zktx.MerkleTreeDepth
+	end := start + zktx.MerkleTreeLeaves
+	if size := k.GetCommitmentTreeSize(ctx); end > size {
+		end = size
+	}
+	if start >= end {
+		return []common.Hash{}
+	}
+	return k.getCommitments(ctx, start, end)
+}
+
+func (k Keeper) getCommitments(ctx sdk.Context, start, end uint64) []common.Hash {
+	cmts := make([]common.Hash, end-start)
+	for i := range cmts {
+		cmts[i] = k.getMerkleNode(ctx, 0, start+uint64(i))
+	}
+	return cmts
+}
+
+// UpdateCommitmentRoot records the roots of the trees that commitments have
+// been appended to since the last recorded root: the final root of every tree
+// filled in the meantime and the current root of the last tree
+func (k Keeper) UpdateCommitmentRoot(ctx sdk.Context) {
+	size := k.GetCommitmentTreeSize(ctx)
+	if size == 0 {
+		return
+	}
+
+	var recorded uint64
+	if latest, found := k.GetLatestCommitmentRoot(ctx); found {
+		recorded = latest.Size
+	}
+	if recorded >= size {
+		return
+	}
+
+	for tree := recorded >> zktx.MerkleTreeDepth; tree <= (size-1)>>zktx.MerkleTreeDepth; tree++ {
+		end := (tree + 1) << zktx.MerkleTreeDepth
+		if end > size {
+			end = size
+		}
+		k.SetCommitmentRoot(ctx, types.NewCommitmentRoot(k.GetCommitmentRoot(ctx, end), ctx.BlockHeight(), end))
+	}
+}
@@ -0,0 +1,161 @@
+package keeper_test
+
+import (
+	"github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+func (suite *KeeperTestSuite) TestCommitmentTree() {
+	var leaves []ethcmn.Hash
+	for i := 0; i < 5; i++ {
+		cmts := ethcmn.BytesToHash([]byte{byte(i + 1)})
+		index, err := suite.app.EvmKeeper.AppendCommitment(suite.ctx, cmts)
+		suite.Require().NoError(err)
+		suite.Require().Equal(uint64(i), index)
+
+		leaves = append(leaves, cmts)
+		suite.Require().Equal(zktx.MerkleRoot(leaves), suite.app.EvmKeeper.GetCommitmentRoot(suite.ctx, uint64(len(leaves))))
+	}
+
+	_, err := suite.app.EvmKeeper.AppendCommitment(suite.ctx, leaves[0])
+	suite.Require().Error(err)
+
+	suite.Require().Equal(uint64(5), suite.app.EvmKeeper.GetCommitmentTreeSize(suite.ctx))
+	suite.Require().Equal(zktx.MerkleRoot(leaves[:3]), suite.app.EvmKeeper.GetCommitmentRoot(suite.ctx, 3))
+	suite.Require().Equal(leaves, suite.app.EvmKeeper.GetAllCommitments(suite.ctx))
+
+	index, found := suite.app.EvmKeeper.GetCommitmentIndex(suite.ctx, leaves[3])
+	suite.Require().True(found)
+	suite.Require().Equal(uint64(3), index)
+
+	_, found = suite.app.EvmKeeper.GetCommitmentTxHash(suite.ctx, leaves[3])
+	suite.Require().False(found)
+	txHash := ethcmn.BytesToHash([]byte("tx"))
+	suite.app.EvmKeeper.SetCommitmentTxHash(suite.ctx, leaves[3], txHash)
+	res, found := suite.app.EvmKeeper.GetCommitmentTxHash(suite.ctx, leaves[3])
+	suite.Require().True(found)
+	suite.Require().Equal(txHash, res)
+
+	// authentication paths against the current and a past tree
+	for size := uint64(1); size <= 5; size++ {
+		root := suite.app.EvmKeeper.GetCommitmentRoot(suite.ctx, size)
+		for i := uint64(0); i < size; i++ {
+			path := suite.app.EvmKeeper.GetCommitmentPath(suite.ctx, i, size)
+			suite.Require().Equal(root, zktx.MerklePathRoot(leaves[i], i, path), "leaf %d of %d", i, size)
+		}
+	}
+}
+
+// TestCommitmentTreeCircuit checks that the roots of the trees are the roots
+// rebuilt from their leaves by the deposit circuit, a new tree being started
+// once one is full.
+func (suite *KeeperTestSuite) TestCommitmentTreeCircuit() {
+	var all []ethcmn.Hash
+	trees := make([][]*ethcmn.Hash, 3)
+	for i := 0; i < 2*zktx.MerkleTreeLeaves+3; i++ {
+		cmts := ethcmn.BytesToHash([]byte{'c', byte(i)})
+		index, err := suite.app.EvmKeeper.AppendCommitment(suite.ctx, cmts)
+		suite.Require().NoError(err)
+		suite.Require().Equal(uint64(i), index)
+
+		all = append(all, cmts)
+		tree := i / zktx.MerkleTreeLeaves
+		trees[tree] = append(trees[tree], &cmts)
+		size := uint64(len(all))
+		root := suite.app.EvmKeeper.GetCommitmentRoot(suite.ctx, size)
+		suite.Require().Equal(zktx.GenRT(trees[tree]), root, "%d leaves", size)
+
+		path := suite.app.EvmKeeper.GetCommitmentPath(suite.ctx, index, size)
+		suite.Require().Equal(root, zktx.MerklePathRoot(cmts, index%zktx.MerkleTreeLeaves, path), "%d leaves", size)
+	}
+
+	suite.Require().Equal(all, suite.app.EvmKeeper.GetAllCommitments(suite.ctx))
+	for tree, leaves := range trees {
+		cmts := suite.app.EvmKeeper.GetTreeCommitments(suite.ctx, uint64(tree))
+		suite.Require().Len(cmts, len(leaves))
+		for i := range leaves {
+			suite.Require().Equal(*leaves[i], cmts[i])
+		}
+	}
+	suite.Require().Empty(suite.app.EvmKeeper.GetTreeCommitments(suite.ctx, 3))
+
+	// the leaves of a full tree keep their path to its final root
+	full := zktx.GenRT(trees[0])
+	for i := uint64(0); i < zktx.MerkleTreeLeaves; i++ {
+		path := suite.app.EvmKeeper.GetCommitmentPath(suite.ctx, i, uint64(len(all)))
+		suite.Require().Equal(full, zktx.MerklePathRoot(all[i], i, path), "leaf %d", i)
+	}
+}
+
+func (suite *KeeperTestSuite) TestCommitmentRoots() {
+	// nothing to record on an empty tree
+	suite.app.EvmKeeper.UpdateCommitmentRoot(suite.ctx)
+	_, found := suite.app.EvmKeeper.GetLatestCommitmentRoot(suite.ctx)
+	suite.Require().False(found)
+
+	var roots []ethcmn.Hash
+	for i := 1; i < zktx.MerkleTreeLeaves; i++ {
+		ctx := suite.ctx.WithBlockHeight(int64(i))
+		_, err := suite.app.EvmKeeper.AppendCommitment(ctx, ethcmn.BytesToHash([]byte{byte(i), byte(i >> 8)}))
+		suite.Require().NoError(err)
+
+		suite.app.EvmKeeper.UpdateCommitmentRoot(ctx)
+		// unchanged tree
+		suite.app.EvmKeeper.UpdateCommitmentRoot(ctx.WithBlockHeight(int64(i) + 1000))
+
+		latest, found := suite.app.EvmKeeper.GetLatestCommitmentRoot(ctx)
+		suite.Require().True(found)
+		suite.Require().Equal(int64(i), latest.Height)
+		suite.Require().Equal(uint64(i), latest.Size)
+		roots = append(roots, latest.Root)
+	}
+
+	for _, root := range roots {
+		suite.Require().True(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, root))
+	}
+
+	// a block filling the first tree and starting the next one records the
+	// final root of the first tree and the root of the second one
+	ctx := suite.ctx.WithBlockHeight(zktx.MerkleTreeLeaves)
+	for i := 0; i < 2; i++ {
+		_, err := suite.app.EvmKeeper.AppendCommitment(ctx, ethcmn.BytesToHash([]byte{byte(i), 0xee}))
+		suite.Require().NoError(err)
+	}
+	suite.app.EvmKeeper.UpdateCommitmentRoot(ctx)
+
+	full := suite.app.EvmKeeper.GetCommitmentRoot(suite.ctx, zktx.MerkleTreeLeaves)
+	suite.Require().True(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, full))
+	second := suite.app.EvmKeeper.GetCommitmentRoot(suite.ctx, zktx.MerkleTreeLeaves+1)
+	suite.Require().True(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, second))
+	latest, found := suite.app.EvmKeeper.GetLatestCommitmentRoot(ctx)
+	suite.Require().True(found)
+	suite.Require().Equal(types.NewCommitmentRoot(second, ctx.BlockHeight(), zktx.MerkleTreeLeaves+1), latest)
+
+	// roots of partial trees beyond the history are dropped, oldest first,
+	// while the final roots of the full trees are kept
+	for i, partial := 0, 0; partial < types.CommitmentRootHistory; i++ {
+		ctx := suite.ctx.WithBlockHeight(int64(2000 + i))
+		_, err := suite.app.EvmKeeper.AppendCommitment(ctx, ethcmn.BytesToHash([]byte{byte(i), byte(i >> 8), 0xff}))
+		suite.Require().NoError(err)
+		suite.app.EvmKeeper.UpdateCommitmentRoot(ctx)
+
+		if latest, _ := suite.app.EvmKeeper.GetLatestCommitmentRoot(ctx); !latest.IsFull() {
+			partial++
+		}
+	}
+	suite.Require().False(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, roots[0]))
+	suite.Require().False(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, second))
+	suite.Require().True(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, full))
+	suite.Require().True(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, suite.app.EvmKeeper.GetCommitmentRoot(suite.ctx, 2*zktx.MerkleTreeLeaves)))
+
+	var partial int
+	for _, root := range suite.app.EvmKeeper.GetAllCommitmentRoots(suite.ctx) {
+		suite.Require().True(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, root.Root))
+		if !root.IsFull() {
+			partial++
+		}
+	}
+	suite.Require().Equal(types.CommitmentRootHistory, partial)
+}
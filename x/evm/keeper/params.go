@@ -15,3 +15,10 @@ func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
 func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
 	k.CommitStateDB.WithContext(ctx).SetParams(params)
 }
+
+// SetMissingParams sets the evm parameters missing from the param space to their
+// default value. Chains started before the proof verification gas parameters
+// were added have no value for them, and GetParams panics until they are set.
+func (k Keeper) SetMissingParams(ctx sdk.Context) {
+	k.CommitStateDB.WithContext(ctx).SetMissingParams()
+}
@@ -1,6 +1,9 @@
 package keeper_test
 
 import (
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	"github.com/cosmos/cosmos-sdk/x/params"
+
 	"github.com/cosmos/ethermint/x/evm/types"
 )
 
@@ -12,3 +15,19 @@ func (suite *KeeperTestSuite) TestParams() {
 	newParams := suite.app.EvmKeeper.GetParams(suite.ctx)
 	suite.Require().Equal(newParams, params)
 }
+
+func (suite *KeeperTestSuite) TestSetMissingParams() {
+	expParams := types.DefaultParams()
+	expParams.EvmDenom = "ara"
+	expParams.SendProofGas = 1
+	suite.app.EvmKeeper.SetParams(suite.ctx, expParams)
+
+	// a chain started before the proof verification gas params
+	store := prefix.NewStore(suite.ctx.KVStore(suite.app.GetKey(params.StoreKey)), []byte(types.DefaultParamspace+"/"))
+	store.Delete(types.ParamStoreKeyMintProofGas)
+	store.Delete(types.ParamStoreKeyDepositProofGas)
+	suite.Require().Panics(func() { suite.app.EvmKeeper.GetParams(suite.ctx) })
+
+	suite.app.EvmKeeper.SetMissingParams(suite.ctx)
+	suite.Require().Equal(expParams, suite.app.EvmKeeper.GetParams(suite.ctx))
+}
@@ -0,0 +1,101 @@
+package keeper
+
+import (
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// pendingSNTimeout is the number of blocks after which the SN of a transaction
+// accepted at CheckTx is released, unless the transaction is rechecked. The
+// mempool rechecks its transactions after every block, so only the SNs of
+// transactions dropped from the mempool expire.
+const pendingSNTimeout = 5
+
+// ----------------------------------------------------------------------------
+// Pending serial numbers
+// SNs spent by the transactions accepted at CheckTx and not processed yet. They
+// are only tracked in memory for the CheckTx state and never consulted at
+// DeliverTx, which only checks the nullifier set.
+// ----------------------------------------------------------------------------
+
+// CheckSN returns an error if the note with the given serial number has been
+// spent by a processed transaction or, at CheckTx, by another transaction
+// pending in the mempool.
+func (k Keeper) CheckSN(ctx sdk.Context, sn common.Hash) error {
+	if k.HasNullifier(ctx, sn) {
+		return sdkerrors.Wrapf(types.ErrSNSpent, "sn %s", sn.Hex())
+	}
+
+	if ctx.IsCheckTx() {
+		if owner, found := k.pendingSNs.get(sn, ctx.BlockHeight()); found && owner != pendingTxHash(ctx) {
+			return sdkerrors.Wrapf(types.ErrSNPending, "sn %s spent by transaction %s", sn.Hex(), owner.Hex())
+		}
+	}
+	return nil
+}
+
+// AddPendingSN records the serial number spent by a transaction accepted at
+// CheckTx, so that other transactions spending it are rejected until the
+// transaction is processed or dropped.
+func (k Keeper) AddPendingSN(ctx sdk.Context, sn common.Hash) {
+	k.pendingSNs.add(sn, pendingTxHash(ctx), ctx.BlockHeight())
+}
+
+func pendingTxHash(ctx sdk.Context) common.Hash {
+	return common.BytesToHash(tmtypes.Tx(ctx.TxBytes()).Hash())
+}
+
+// pendingSNSet maps the pending SNs to the hash of the transaction spending
+// them and the height it was last checked at.
+type pendingSNSet struct {
+	mtx     sync.Mutex
+	entries map[common.Hash]pendingSN
+	pruned  int64
+}
+
+type pendingSN struct {
+	txHash common.Hash
+	height int64
+}
+
+func newPendingSNSet() *pendingSNSet {
+	return &pendingSNSet{
+		entries: make(map[common.Hash]pendingSN),
+	}
+}
+
+func (s *pendingSNSet) get(sn common.Hash, height int64) (common.Hash, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	entry, found := s.entries[sn]
+	if !found || height > entry.height+pendingSNTimeout {
+		return common.Hash{}, false
+	}
+	return entry.txHash, true
+}
+
+func (s *pendingSNSet) add(sn, txHash common.Hash, height int64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	// drop the expired entries once per block
+	if height > s.pruned {
+		for key, entry := range s.entries {
+			if height > entry.height+pendingSNTimeout {
+				delete(s.entries, key)
+			}
+		}
+		s.pruned = height
+	}
+
+	s.entries[sn] = pendingSN{txHash: txHash, height: height}
+}
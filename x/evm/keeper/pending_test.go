@@ -0,0 +1,35 @@
+package keeper_test
+
+import (
+	"errors"
+
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+func (suite *KeeperTestSuite) TestPendingSN() {
+	sn := ethcmn.BytesToHash([]byte("pending sn"))
+	checkTx1 := suite.ctx.WithIsCheckTx(true).WithTxBytes([]byte("tx1"))
+	checkTx2 := checkTx1.WithTxBytes([]byte("tx2"))
+
+	suite.Require().NoError(suite.app.EvmKeeper.CheckSN(checkTx1, sn))
+	suite.app.EvmKeeper.AddPendingSN(checkTx1, sn)
+
+	// another transaction spending the SN is rejected at CheckTx only, the
+	// transaction itself passes its recheck
+	suite.Require().True(errors.Is(suite.app.EvmKeeper.CheckSN(checkTx2, sn), types.ErrSNPending))
+	suite.Require().NoError(suite.app.EvmKeeper.CheckSN(checkTx1, sn))
+	suite.Require().NoError(suite.app.EvmKeeper.CheckSN(suite.ctx.WithTxBytes([]byte("tx2")), sn))
+
+	// the SN of a transaction that isn't rechecked expires
+	expired := checkTx2.WithBlockHeight(checkTx1.BlockHeight() + 6)
+	suite.Require().NoError(suite.app.EvmKeeper.CheckSN(expired, sn))
+	suite.app.EvmKeeper.AddPendingSN(expired, sn)
+	suite.Require().True(errors.Is(suite.app.EvmKeeper.CheckSN(checkTx1.WithBlockHeight(expired.BlockHeight()), sn), types.ErrSNPending))
+
+	// a processed SN is spent
+	suite.app.EvmKeeper.SetNullifier(suite.ctx, types.NewNullifier(sn, 1, ethcmn.Hash{}))
+	suite.Require().True(errors.Is(suite.app.EvmKeeper.CheckSN(expired, sn), types.ErrSNSpent))
+	suite.Require().True(errors.Is(suite.app.EvmKeeper.CheckSN(suite.ctx, sn), types.ErrSNSpent))
+}
@@ -0,0 +1,106 @@
+package keeper
+
+import (
+	"crypto/ecdsa"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// proofCacheSize is the number of verified transactions remembered between
+// CheckTx and DeliverTx. It is above the default size of the mempool.
+const proofCacheSize = 10000
+
+// ----------------------------------------------------------------------------
+// Proof verification
+// The zk-SNARK proof of a shielded transaction is verified once by the ante
+// handler at CheckTx and the result cached for the ante handler and the msg
+// handler at DeliverTx. Simulations and ReCheckTx don't fill the cache, so a
+// transaction that only went through them is verified again at DeliverTx.
+// ----------------------------------------------------------------------------
+
+// VerifyProof verifies the zk-SNARK proof of a shielded transaction against the
+// note it spends. Valid proofs are cached by the hash of the transaction bytes,
+// which commit to the proof and to all its public inputs, so that the proof of a
+// transaction is only checked once. Proofs verified while simulating or at
+// ReCheckTx are not cached. The caller must check that the spent note is set.
+func (k Keeper) VerifyProof(ctx sdk.Context, msg types.MsgEthereumTx, simulate bool) error {
+	var txHash common.Hash
+	hasBytes := len(ctx.TxBytes()) > 0
+	if hasBytes {
+		txHash = common.BytesToHash(tmtypes.Tx(ctx.TxBytes()).Hash())
+		if k.proofs.has(txHash) {
+			return nil
+		}
+	}
+
+	if err := k.verifyProof(msg); err != nil {
+		return err
+	}
+
+	if hasBytes && !simulate && !ctx.IsReCheckTx() {
+		k.proofs.add(txHash)
+	}
+	return nil
+}
+
+func (k Keeper) verifyProof(msg types.MsgEthereumTx) error {
+	switch data := msg.Shielded.(type) {
+	case *types.MintTxData:
+		return k.Verifier.VerifyMintProof(&data.CMTOld, &data.SN, &data.CMT, data.Value, data.Proof)
+	case *types.SendTxData:
+		return k.Verifier.VerifySendProof(&data.SN, &data.CMTS, data.Proof, &data.CMTOld, &data.CMT)
+	case *types.DepositTxData:
+		pk := &ecdsa.PublicKey{Curve: crypto.S256(), X: data.X, Y: data.Y}
+		return k.Verifier.VerifyDepositProof(pk, data.RTcmt, &data.CMTOld, &data.SN, &data.CMT, &data.SNS, data.Proof)
+	case *types.RedeemTxData:
+		return k.Verifier.VerifyRedeemProof(&data.CMTOld, &data.SN, &data.CMT, data.Value, data.Proof)
+	default:
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unsupported shielded transaction type %T", data)
+	}
+}
+
+// proofCache holds the hashes of the transactions whose proof has been
+// verified. Once full, the oldest hashes are evicted first.
+type proofCache struct {
+	mtx      sync.Mutex
+	verified map[common.Hash]bool
+	order    []common.Hash
+	next     int
+}
+
+func newProofCache(size int) *proofCache {
+	return &proofCache{
+		verified: make(map[common.Hash]bool, size),
+		order:    make([]common.Hash, size),
+	}
+}
+
+func (c *proofCache) has(txHash common.Hash) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.verified[txHash]
+}
+
+func (c *proofCache) add(txHash common.Hash) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.verified[txHash] {
+		return
+	}
+
+	delete(c.verified, c.order[c.next])
+	c.order[c.next] = txHash
+	c.verified[txHash] = true
+	c.next = (c.next + 1) % len(c.order)
+}
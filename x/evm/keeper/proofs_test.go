@@ -0,0 +1,89 @@
+package keeper_test
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// countingVerifier accepts the proofs equal to valid and counts the mint
+// proofs it checks.
+type countingVerifier struct {
+	valid []byte
+	calls int
+}
+
+func (v *countingVerifier) VerifyMintProof(_, _, _ *ethcmn.Hash, _ uint64, proof []byte) error {
+	v.calls++
+	if string(proof) != string(v.valid) {
+		return errors.New("invalid proof")
+	}
+	return nil
+}
+
+func (v *countingVerifier) VerifySendProof(*ethcmn.Hash, *ethcmn.Hash, []byte, *ethcmn.Hash, *ethcmn.Hash) error {
+	return nil
+}
+
+func (v *countingVerifier) VerifyDepositProof(*ecdsa.PublicKey, ethcmn.Hash, *ethcmn.Hash, *ethcmn.Hash, *ethcmn.Hash, *ethcmn.Hash, []byte) error {
+	return nil
+}
+
+func (v *countingVerifier) VerifyRedeemProof(*ethcmn.Hash, *ethcmn.Hash, *ethcmn.Hash, uint64, []byte) error {
+	return nil
+}
+
+func (suite *KeeperTestSuite) TestVerifyProof() {
+	verifier := &countingVerifier{valid: []byte("proof")}
+	k := suite.app.EvmKeeper
+	k.Verifier = verifier
+
+	newMint := func(proof string) types.MsgEthereumTx {
+		msg := types.NewMsgEthereumTx(0, &suite.address, big.NewInt(0), 100000, big.NewInt(0), nil)
+		msg.SetTxCode(types.MintTx)
+		sn, cmtOld, cmt := ethcmn.BytesToHash([]byte("sn")), ethcmn.BytesToHash([]byte("cmt old")), ethcmn.BytesToHash([]byte("cmt"))
+		msg.SetZKSN(&sn)
+		msg.SetZKCMTOld(&cmtOld)
+		msg.SetZKCMT(&cmt)
+		msg.SetZKProof([]byte(proof))
+		return msg
+	}
+
+	// verified once, then served from the cache
+	ctx := suite.ctx.WithTxBytes([]byte("tx1"))
+	suite.Require().NoError(k.VerifyProof(ctx, newMint("proof"), false))
+	suite.Require().NoError(k.VerifyProof(ctx.WithIsCheckTx(true), newMint("proof"), false))
+	suite.Require().Equal(1, verifier.calls)
+
+	// another transaction is verified again
+	suite.Require().NoError(k.VerifyProof(suite.ctx.WithTxBytes([]byte("tx2")), newMint("proof"), false))
+	suite.Require().Equal(2, verifier.calls)
+
+	// invalid proofs aren't cached
+	ctx = suite.ctx.WithTxBytes([]byte("tx3"))
+	suite.Require().Error(k.VerifyProof(ctx, newMint("forged"), false))
+	suite.Require().Error(k.VerifyProof(ctx, newMint("forged"), false))
+	suite.Require().Equal(4, verifier.calls)
+
+	// nor are transactions without bytes
+	suite.Require().NoError(k.VerifyProof(suite.ctx, newMint("proof"), false))
+	suite.Require().NoError(k.VerifyProof(suite.ctx, newMint("proof"), false))
+	suite.Require().Equal(6, verifier.calls)
+
+	// a proof only verified while simulating or at ReCheckTx isn't cached, and
+	// is verified again at DeliverTx
+	ctx = suite.ctx.WithTxBytes([]byte("tx5"))
+	suite.Require().NoError(k.VerifyProof(ctx.WithIsCheckTx(true), newMint("proof"), true))
+	suite.Require().NoError(k.VerifyProof(ctx.WithIsReCheckTx(true), newMint("proof"), false))
+	suite.Require().Equal(8, verifier.calls)
+	suite.Require().NoError(k.VerifyProof(ctx, newMint("proof"), false))
+	suite.Require().Equal(9, verifier.calls)
+
+	msg := newMint("proof")
+	msg.SetTxCode(types.PublicTx)
+	suite.Require().Error(k.VerifyProof(suite.ctx.WithTxBytes([]byte("tx4")), msg, false))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/cosmos/ethermint/utils"
 	"github.com/cosmos/ethermint/version"
 	"github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -45,6 +46,14 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return queryAccount(ctx, path, keeper)
 		case types.QueryExportAccount:
 			return queryExportAccount(ctx, path, keeper)
+		case types.QueryResSN:
+			return querySN(ctx, path, keeper)
+		case types.QueryCommitmentProof:
+			return queryCommitmentProof(ctx, path, keeper)
+		case types.QueryNotes:
+			return queryNotes(ctx, path, keeper)
+		case types.QueryParameters:
+			return queryParameters(ctx, keeper)
 		default:
 			return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "unknown query endpoint")
 		}
@@ -226,3 +235,103 @@ func queryExportAccount(ctx sdk.Context, path []string, keeper Keeper) ([]byte,
 
 	return bz, nil
 }
+
+func querySN(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	if len(path) < 2 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "missing serial number")
+	}
+
+	sn, err := types.ParseSN(path[1])
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	height, spent := keeper.GetNullifier(ctx, sn)
+	res := types.QuerySN{
+		SN:     sn,
+		Spent:  spent,
+		Height: height,
+	}
+	// amino encodes hashes as base64 but decodes them as hex, use the hex
+	// encoding of the standard library both ways
+	bz, err := json.MarshalIndent(res, "", "\t")
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return bz, nil
+}
+
+func queryNotes(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	if len(path) < 2 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "missing address")
+	}
+	if !ethcmn.IsHexAddress(path[1]) {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid address %s", path[1])
+	}
+
+	addr := ethcmn.HexToAddress(path[1])
+	res := types.QueryResNotes{
+		Address: addr,
+		Notes:   keeper.GetNotes(ctx, addr),
+	}
+	// amino encodes hashes and addresses as base64 but decodes them as hex, use
+	// the hex encoding of the standard library both ways
+	bz, err := json.MarshalIndent(res, "", "\t")
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return bz, nil
+}
+
+func queryParameters(ctx sdk.Context, keeper Keeper) ([]byte, error) {
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, keeper.GetParams(ctx))
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return bz, nil
+}
+
+func queryCommitmentProof(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	if len(path) < 2 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "missing send commitment")
+	}
+
+	cmts, err := types.ParseCMTS(path[1])
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	index, found := keeper.GetCommitmentIndex(ctx, cmts)
+	if !found {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "send commitment %s not in the commitment tree", cmts.Hex())
+	}
+
+	// the commitment is proven against the current root of its tree, which is
+	// the final one if the tree is full
+	tree := index >> zktx.MerkleTreeDepth
+	leaves := keeper.GetTreeCommitments(ctx, tree)
+	size := tree<<zktx.MerkleTreeDepth + uint64(len(leaves))
+
+	txHash, _ := keeper.GetCommitmentTxHash(ctx, cmts)
+	res := types.QueryResCommitmentProof{
+		CMTS:   cmts,
+		Tree:   tree,
+		Index:  index % zktx.MerkleTreeLeaves,
+		Path:   keeper.GetCommitmentPath(ctx, index, size),
+		Leaves: leaves,
+		Root:   keeper.GetCommitmentRoot(ctx, size),
+		Size:   uint64(len(leaves)),
+		TxHash: txHash,
+	}
+	// amino encodes hashes as base64 but decodes them as hex, use the hex
+	// encoding of the standard library both ways
+	bz, err := json.MarshalIndent(res, "", "\t")
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return bz, nil
+}
@@ -1,9 +1,12 @@
 package keeper_test
 
 import (
+	"encoding/json"
 	"math/big"
 
 	"github.com/cosmos/ethermint/x/evm/types"
+	"github.com/cosmos/ethermint/zktx"
+	ethcmn "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -36,6 +39,25 @@ func (suite *KeeperTestSuite) TestQuerier() {
 		{"logs", []string{types.QueryLogs, "0x0"}, func() {}, true},
 		{"account", []string{types.QueryAccount, "0x0"}, func() {}, true},
 		{"exportAccount", []string{types.QueryExportAccount, "0x0"}, func() {}, true},
+		{"sn", []string{types.QueryResSN, hex}, func() {
+			suite.app.EvmKeeper.SetNullifier(suite.ctx, types.NewNullifier(ethcmn.HexToHash(hex), suite.ctx.BlockHeight(), ethcmn.HexToHash(hex)))
+		}, true},
+		{"sn not spent", []string{types.QueryResSN, hex[2:]}, func() {}, true},
+		{"sn invalid length", []string{types.QueryResSN, "0x1234"}, func() {}, false},
+		{"sn invalid hex", []string{types.QueryResSN, "0xzz"}, func() {}, false},
+		{"sn missing", []string{types.QueryResSN}, func() {}, false},
+		{"commitment proof", []string{types.QueryCommitmentProof, hex}, func() {
+			_, _ = suite.app.EvmKeeper.AppendCommitment(suite.ctx, ethcmn.HexToHash(hex))
+		}, true},
+		{"commitment proof unknown cmts", []string{types.QueryCommitmentProof, hex}, func() {}, false},
+		{"commitment proof invalid cmts", []string{types.QueryCommitmentProof, "0x1234"}, func() {}, false},
+		{"notes", []string{types.QueryNotes, addrHex}, func() {
+			suite.app.EvmKeeper.SetNote(suite.ctx, types.NewNote(ethcmn.HexToAddress(addrHex), ethcmn.HexToHash(hex)))
+		}, true},
+		{"notes empty", []string{types.QueryNotes, addrHex}, func() {}, true},
+		{"notes invalid address", []string{types.QueryNotes, "0x1234"}, func() {}, false},
+		{"notes missing", []string{types.QueryNotes}, func() {}, false},
+		{"parameters", []string{types.QueryParameters}, func() {}, true},
 		{"unknown request", []string{"other"}, func() {}, false},
 	}
 
@@ -54,6 +76,14 @@ func (suite *KeeperTestSuite) TestQuerier() {
 				//nolint
 				suite.Require().NoError(err, "valid test %d failed: %s", i, tc.msg)
 				suite.Require().NotZero(len(bz))
+
+				// the SN response is decoded like eth_getSN and the CLI do
+				if tc.path[0] == types.QueryResSN {
+					var res types.QuerySN
+					suite.Require().NoError(json.Unmarshal(bz, &res), tc.msg)
+					suite.Require().Equal(ethcmn.HexToHash(hex), res.SN, tc.msg)
+					suite.Require().Equal(tc.msg == "sn", res.Spent, tc.msg)
+				}
 			} else {
 				//nolint
 				suite.Require().Error(err, "invalid test %d passed: %s", i, tc.msg)
@@ -61,3 +91,68 @@ func (suite *KeeperTestSuite) TestQuerier() {
 		})
 	}
 }
+
+func (suite *KeeperTestSuite) TestQueryCommitmentProof() {
+	// sends fill a first tree and start a second one, three per block, the
+	// deposits claim one of each tree
+	var cmts []ethcmn.Hash
+	for i := 0; i < zktx.MerkleTreeLeaves+8; i++ {
+		cmts = append(cmts, ethcmn.BytesToHash([]byte{byte(i + 1)}))
+		ctx := suite.ctx.WithBlockHeight(int64(1 + i/3))
+		_, err := suite.app.EvmKeeper.AppendCommitment(ctx, cmts[i])
+		suite.Require().NoError(err)
+		suite.app.EvmKeeper.UpdateCommitmentRoot(ctx)
+	}
+
+	testCases := []struct {
+		msg     string
+		claimed int
+		tree    uint64
+		leaves  []ethcmn.Hash
+	}{
+		{"full tree", 1, 0, cmts[:zktx.MerkleTreeLeaves]},
+		{"current tree", zktx.MerkleTreeLeaves + 5, 1, cmts[zktx.MerkleTreeLeaves:]},
+	}
+
+	for _, tc := range testCases {
+		claimed := cmts[tc.claimed]
+		bz, err := suite.querier(suite.ctx, []string{types.QueryCommitmentProof, claimed.Hex()}, abci.RequestQuery{})
+		suite.Require().NoError(err, tc.msg)
+		var res types.QueryResCommitmentProof
+		suite.Require().NoError(json.Unmarshal(bz, &res), tc.msg)
+		suite.Require().Equal(tc.tree, res.Tree, tc.msg)
+		suite.Require().Equal(uint64(tc.claimed%zktx.MerkleTreeLeaves), res.Index, tc.msg)
+		suite.Require().Equal(tc.leaves, res.Leaves, tc.msg)
+		suite.Require().Equal(uint64(len(tc.leaves)), res.Size, tc.msg)
+		suite.Require().Equal(claimed, res.Leaves[res.Index], tc.msg)
+
+		// the deposit prover rebuilds the root from the leaves, which is a root
+		// recorded by the keeper, and the authentication path is not a substitute
+		leaves := make([]*ethcmn.Hash, len(res.Leaves))
+		for i := range res.Leaves {
+			leaves[i] = &res.Leaves[i]
+		}
+		suite.Require().Equal(res.Root, zktx.GenRT(leaves), tc.msg)
+		suite.Require().True(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, res.Root), tc.msg)
+		suite.Require().Equal(res.Root, zktx.MerklePathRoot(claimed, res.Index, res.Path), tc.msg)
+
+		path := make([]*ethcmn.Hash, len(res.Path))
+		for i := range res.Path {
+			path[i] = &res.Path[i]
+		}
+		suite.Require().False(suite.app.EvmKeeper.HasCommitmentRoot(suite.ctx, zktx.GenRT(path)), tc.msg)
+	}
+}
+
+func (suite *KeeperTestSuite) TestQueryNotes() {
+	owner := ethcmn.HexToAddress(addrHex)
+	cmt := ethcmn.BytesToHash([]byte("cmt"))
+	suite.app.EvmKeeper.SetNote(suite.ctx, types.NewNote(owner, cmt))
+
+	bz, err := suite.querier(suite.ctx, []string{types.QueryNotes, addrHex}, abci.RequestQuery{})
+	suite.Require().NoError(err)
+	var res types.QueryResNotes
+	suite.Require().NoError(json.Unmarshal(bz, &res))
+	suite.Require().Equal(owner, res.Address)
+	suite.Require().Equal([]ethcmn.Hash{cmt}, res.Notes)
+}
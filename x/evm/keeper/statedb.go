@@ -32,6 +32,11 @@ func (k *Keeper) SubBalance(ctx sdk.Context, addr ethcmn.Address, amount *big.In
 	k.CommitStateDB.WithContext(ctx).SubBalance(addr, amount)
 }
 
+// SetShieldedSupply calls CommitStateDB.SetShieldedSupply using the passed in context
+func (k *Keeper) SetShieldedSupply(ctx sdk.Context, supply *big.Int) {
+	k.CommitStateDB.WithContext(ctx).SetShieldedSupply(supply)
+}
+
 // SetNonce calls CommitStateDB.SetNonce using the passed in context
 func (k *Keeper) SetNonce(ctx sdk.Context, addr ethcmn.Address, nonce uint64) {
 	k.CommitStateDB.WithContext(ctx).SetNonce(addr, nonce)
@@ -86,12 +91,9 @@ func (k *Keeper) GetBalance(ctx sdk.Context, addr ethcmn.Address) *big.Int {
 	return k.CommitStateDB.WithContext(ctx).GetBalance(addr)
 }
 
-func (k *Keeper) GetCMTBalance(addr ethcmn.Address) ethcmn.Hash {
-	stateObject := k.CommitStateDB.GetOrNewStateObject(addr)
-	if stateObject != nil {
-		return stateObject.CMTBalance()
-	}
-	return ethcmn.Hash{}
+// GetShieldedSupply calls CommitStateDB.GetShieldedSupply using the passed in context
+func (k *Keeper) GetShieldedSupply(ctx sdk.Context) *big.Int {
+	return k.CommitStateDB.WithContext(ctx).GetShieldedSupply()
 }
 
 
@@ -71,14 +71,16 @@ type AppModule struct {
 	AppModuleBasic
 	keeper Keeper
 	ak     types.AccountKeeper
+	sk     types.SupplyKeeper
 }
 
 // NewAppModule creates a new AppModule Object
-func NewAppModule(k Keeper, ak types.AccountKeeper) AppModule {
+func NewAppModule(k Keeper, ak types.AccountKeeper, sk types.SupplyKeeper) AppModule {
 	return AppModule{
 		AppModuleBasic: AppModuleBasic{},
 		keeper:         k,
 		ak:             ak,
+		sk:             sk,
 	}
 }
 
@@ -88,7 +90,9 @@ func (AppModule) Name() string {
 }
 
 // RegisterInvariants interface for registering invariants
-func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {}
+func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {
+	keeper.RegisterInvariants(ir, am.keeper, am.ak, am.sk)
+}
 
 // Route specifies path for transactions
 func (am AppModule) Route() string {
@@ -26,7 +26,7 @@ var testJSON = `{
  	}`
 
 func (suite *EvmTestSuite) TestInitGenesis() {
-	am := evm.NewAppModule(suite.app.EvmKeeper, suite.app.AccountKeeper)
+	am := evm.NewAppModule(suite.app.EvmKeeper, suite.app.AccountKeeper, suite.app.SupplyKeeper)
 	in := json.RawMessage([]byte(testJSON))
 	_ = am.InitGenesis(suite.ctx, in)
 
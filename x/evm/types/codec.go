@@ -13,6 +13,11 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgEthereumTx{}, "ethermint/MsgEthereumTx", nil)
 	cdc.RegisterConcrete(MsgEthermint{}, "ethermint/MsgEthermint", nil)
 	cdc.RegisterConcrete(TxData{}, "ethermint/TxData", nil)
+	cdc.RegisterInterface((*ShieldedTxData)(nil), nil)
+	cdc.RegisterConcrete(&MintTxData{}, "ethermint/MintTxData", nil)
+	cdc.RegisterConcrete(&SendTxData{}, "ethermint/SendTxData", nil)
+	cdc.RegisterConcrete(&DepositTxData{}, "ethermint/DepositTxData", nil)
+	cdc.RegisterConcrete(&RedeemTxData{}, "ethermint/RedeemTxData", nil)
 	cdc.RegisterConcrete(ChainConfig{}, "ethermint/ChainConfig", nil)
 }
 
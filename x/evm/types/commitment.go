@@ -0,0 +1,73 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+
+	"github.com/cosmos/ethermint/zktx"
+)
+
+// CommitmentRootHistory is the number of recent roots of partial commitment
+// trees, recorded at the end of the blocks that appended send commitments, that
+// deposits can be proven against. The final root of every full tree is kept
+// besides them, so that its commitments can always be deposited.
+const CommitmentRootHistory = 100
+
+// CommitmentRoot defines the root of a commitment tree at the end of a block
+// together with the number of commitments appended to all the trees up to its
+// last leaf. It is used for import/export of the recorded roots.
+type CommitmentRoot struct {
+	Root   ethcmn.Hash `json:"root"`
+	Height int64       `json:"height"`
+	Size   uint64      `json:"size"`
+}
+
+// NewCommitmentRoot creates a new CommitmentRoot instance.
+func NewCommitmentRoot(root ethcmn.Hash, height int64, size uint64) CommitmentRoot {
+	return CommitmentRoot{
+		Root:   root,
+		Height: height,
+		Size:   size,
+	}
+}
+
+// Validate performs a basic validation of the CommitmentRoot fields.
+func (cr CommitmentRoot) Validate() error {
+	if bytes.Equal(cr.Root.Bytes(), ethcmn.Hash{}.Bytes()) {
+		return errors.New("root cannot be empty")
+	}
+	if cr.Height < 0 {
+		return fmt.Errorf("height cannot be negative %d", cr.Height)
+	}
+	if cr.Size == 0 {
+		return errors.New("size cannot be zero")
+	}
+	return nil
+}
+
+// IsFull returns true if the root is the final root of a full tree.
+func (cr CommitmentRoot) IsFull() bool {
+	return cr.Size%zktx.MerkleTreeLeaves == 0
+}
+
+// Encode returns the store value of the root: the root followed by the big
+// endian height.
+func (cr CommitmentRoot) Encode() []byte {
+	return append(cr.Root.Bytes(), sdk.Uint64ToBigEndian(uint64(cr.Height))...)
+}
+
+// DecodeCommitmentRoot decodes the store value of the root recorded for the
+// given size.
+func DecodeCommitmentRoot(size uint64, bz []byte) CommitmentRoot {
+	return NewCommitmentRoot(
+		ethcmn.BytesToHash(bz[:ethcmn.HashLength]),
+		int64(binary.BigEndian.Uint64(bz[ethcmn.HashLength:])),
+		size,
+	)
+}
@@ -15,4 +15,31 @@ var (
 
 	// ErrInvalidChainConfig returns an error resulting from an invalid ChainConfig.
 	ErrInvalidChainConfig = sdkerrors.Register(ModuleName, 4, "invalid chain configuration")
+
+	// ErrUnknownCommitmentRoot returns an error if a deposit is proven against a commitment tree
+	// root that isn't one of the recent roots.
+	ErrUnknownCommitmentRoot = sdkerrors.Register(ModuleName, 5, "unknown commitment tree root")
+
+	// ErrDuplicateCommitment returns an error if a send commitment is already in the commitment tree.
+	ErrDuplicateCommitment = sdkerrors.Register(ModuleName, 6, "duplicate send commitment")
+
+	// ErrUnknownNote returns an error if a shielded transaction spends a commitment that isn't an
+	// unspent note of its sender.
+	ErrUnknownNote = sdkerrors.Register(ModuleName, 8, "unknown unspent note")
+
+	// ErrNoteDeposited returns an error if the send commitment claimed by a deposit has already
+	// been deposited.
+	ErrNoteDeposited = sdkerrors.Register(ModuleName, 9, "send commitment already deposited")
+
+	// ErrMalformedShieldedTx returns an error if a shielded transaction is missing one of the zk
+	// fields of its code or isn't sent to the shielded pool address.
+	ErrMalformedShieldedTx = sdkerrors.Register(ModuleName, 10, "malformed shielded transaction")
+
+	// ErrSNSpent returns an error if a shielded transaction spends a note whose serial number is
+	// in the nullifier set.
+	ErrSNSpent = sdkerrors.Register(ModuleName, 11, "serial number already spent")
+
+	// ErrSNPending returns an error if a shielded transaction spends a note whose serial number
+	// is spent by another transaction pending in the mempool.
+	ErrSNPending = sdkerrors.Register(ModuleName, 12, "serial number spent by a pending transaction")
 )
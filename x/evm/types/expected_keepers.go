@@ -3,13 +3,21 @@ package types
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+	supplyexported "github.com/cosmos/cosmos-sdk/x/supply/exported"
 )
 
 // AccountKeeper defines the expected account keeper interface
 type AccountKeeper interface {
+	NewAccount(ctx sdk.Context, acc authexported.Account) authexported.Account
 	NewAccountWithAddress(ctx sdk.Context, addr sdk.AccAddress) authexported.Account
 	GetAllAccounts(ctx sdk.Context) (accounts []authexported.Account)
+	IterateAccounts(ctx sdk.Context, cb func(account authexported.Account) (stop bool))
 	GetAccount(ctx sdk.Context, addr sdk.AccAddress) authexported.Account
 	SetAccount(ctx sdk.Context, account authexported.Account)
 	RemoveAccount(ctx sdk.Context, account authexported.Account)
 }
+
+// SupplyKeeper defines the expected supply keeper interface
+type SupplyKeeper interface {
+	GetSupply(ctx sdk.Context) supplyexported.SupplyI
+}
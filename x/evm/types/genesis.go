@@ -17,6 +17,14 @@ type (
 		TxsLogs     []TransactionLogs `json:"txs_logs"`
 		ChainConfig ChainConfig       `json:"chain_config"`
 		Params      Params            `json:"params"`
+		Nullifiers  []Nullifier       `json:"nullifiers"`
+		SpendingTxs []SpendingTx      `json:"spending_txs"`
+		Notes       []Note            `json:"notes"`
+		// total amount of the EVM denomination held in the shielded pool
+		ShieldedSupply *big.Int `json:"shielded_supply"`
+		// send commitments of the commitment tree in leaf order and its recent roots
+		Commitments     []ethcmn.Hash    `json:"commitments"`
+		CommitmentRoots []CommitmentRoot `json:"commitment_roots"`
 	}
 
 	// GenesisAccount defines an account to be initialized in the genesis state.
@@ -25,7 +33,7 @@ type (
 	GenesisAccount struct {
 		Address ethcmn.Address `json:"address"`
 		Balance *big.Int       `json:"balance"`
-		Code    hexutil.Bytes  `json:"Code,omitempty"`
+		Code    hexutil.Bytes  `json:"code,omitempty"`
 		Storage Storage        `json:"storage,omitempty"`
 	}
 )
@@ -52,10 +60,16 @@ func (ga GenesisAccount) Validate() error {
 // chain config values.
 func DefaultGenesisState() GenesisState {
 	return GenesisState{
-		Accounts:    []GenesisAccount{},
-		TxsLogs:     []TransactionLogs{},
-		ChainConfig: DefaultChainConfig(),
-		Params:      DefaultParams(),
+		Accounts:        []GenesisAccount{},
+		TxsLogs:         []TransactionLogs{},
+		ChainConfig:     DefaultChainConfig(),
+		Params:          DefaultParams(),
+		Nullifiers:      []Nullifier{},
+		SpendingTxs:     []SpendingTx{},
+		Notes:           []Note{},
+		ShieldedSupply:  big.NewInt(0),
+		Commitments:     []ethcmn.Hash{},
+		CommitmentRoots: []CommitmentRoot{},
 	}
 }
 
@@ -64,6 +78,7 @@ func DefaultGenesisState() GenesisState {
 func (gs GenesisState) Validate() error {
 	seenAccounts := make(map[string]bool)
 	seenTxs := make(map[string]bool)
+	seenNullifiers := make(map[string]bool)
 	for _, acc := range gs.Accounts {
 		if seenAccounts[acc.Address.String()] {
 			return fmt.Errorf("duplicated genesis account %s", acc.Address.String())
@@ -84,6 +99,72 @@ func (gs GenesisState) Validate() error {
 
 		seenTxs[tx.Hash.String()] = true
 	}
+	for _, nullifier := range gs.Nullifiers {
+		if seenNullifiers[nullifier.SN.String()] {
+			return fmt.Errorf("duplicated nullifier %s", nullifier.SN.String())
+		}
+
+		if err := nullifier.Validate(); err != nil {
+			return fmt.Errorf("invalid nullifier %s: %w", nullifier.SN.String(), err)
+		}
+
+		seenNullifiers[nullifier.SN.String()] = true
+	}
+
+	seenSpendingTxs := make(map[string]bool)
+	for _, tx := range gs.SpendingTxs {
+		if seenSpendingTxs[tx.TxHash.String()] {
+			return fmt.Errorf("duplicated spending transaction %s", tx.TxHash.String())
+		}
+
+		if err := tx.Validate(); err != nil {
+			return fmt.Errorf("invalid spending transaction %s: %w", tx.TxHash.String(), err)
+		}
+
+		seenSpendingTxs[tx.TxHash.String()] = true
+	}
+
+	seenNotes := make(map[string]bool)
+	for _, note := range gs.Notes {
+		key := note.Owner.String() + note.CMT.String()
+		if seenNotes[key] {
+			return fmt.Errorf("duplicated note %s of %s", note.CMT.String(), note.Owner.String())
+		}
+
+		if err := note.Validate(); err != nil {
+			return fmt.Errorf("invalid note %s of %s: %w", note.CMT.String(), note.Owner.String(), err)
+		}
+
+		seenNotes[key] = true
+	}
+
+	seenCommitments := make(map[string]bool)
+	for _, cmts := range gs.Commitments {
+		if seenCommitments[cmts.String()] {
+			return fmt.Errorf("duplicated commitment %s", cmts.String())
+		}
+		seenCommitments[cmts.String()] = true
+	}
+	seenRoots := make(map[string]bool)
+	for i, root := range gs.CommitmentRoots {
+		if seenRoots[root.Root.String()] {
+			return fmt.Errorf("duplicated commitment root %s", root.Root.String())
+		}
+		if err := root.Validate(); err != nil {
+			return fmt.Errorf("invalid commitment root %s: %w", root.Root.String(), err)
+		}
+		if root.Size > uint64(len(gs.Commitments)) {
+			return fmt.Errorf("commitment root %s holds %d commitments, only %d in genesis", root.Root.String(), root.Size, len(gs.Commitments))
+		}
+		if i > 0 && (root.Size <= gs.CommitmentRoots[i-1].Size || root.Height < gs.CommitmentRoots[i-1].Height) {
+			return fmt.Errorf("commitment root %s isn't sorted by size and height", root.Root.String())
+		}
+		seenRoots[root.Root.String()] = true
+	}
+
+	if gs.ShieldedSupply != nil && gs.ShieldedSupply.Sign() == -1 {
+		return errors.New("shielded supply cannot be negative")
+	}
 
 	if err := gs.ChainConfig.Validate(); err != nil {
 		return err
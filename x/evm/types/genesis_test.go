@@ -11,6 +11,7 @@ import (
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/cosmos/ethermint/crypto/ethsecp256k1"
+	"github.com/cosmos/ethermint/zktx"
 )
 
 func TestValidateGenesisAccount(t *testing.T) {
@@ -235,6 +236,127 @@ func TestValidateGenesis(t *testing.T) {
 			},
 			expPass: false,
 		},
+		{
+			name: "duplicated nullifier",
+			genState: GenesisState{
+				ChainConfig: DefaultChainConfig(),
+				Params:      DefaultParams(),
+				Nullifiers: []Nullifier{
+					NewNullifier(ethcmn.BytesToHash([]byte{1, 2, 3}), 1, ethcmn.BytesToHash([]byte{4})),
+					NewNullifier(ethcmn.BytesToHash([]byte{1, 2, 3}), 2, ethcmn.BytesToHash([]byte{5})),
+				},
+			},
+			expPass: false,
+		},
+		{
+			name: "invalid nullifier",
+			genState: GenesisState{
+				ChainConfig: DefaultChainConfig(),
+				Params:      DefaultParams(),
+				Nullifiers:  []Nullifier{NewNullifier(ethcmn.Hash{}, 1, ethcmn.BytesToHash([]byte{4}))},
+			},
+			expPass: false,
+		},
+		{
+			name: "nullifier without transaction",
+			genState: GenesisState{
+				ChainConfig: DefaultChainConfig(),
+				Params:      DefaultParams(),
+				Nullifiers:  []Nullifier{NewNullifier(ethcmn.BytesToHash([]byte{1, 2, 3}), 1, ethcmn.Hash{})},
+			},
+			expPass: false,
+		},
+		{
+			name: "duplicated spending transaction",
+			genState: GenesisState{
+				ChainConfig: DefaultChainConfig(),
+				Params:      DefaultParams(),
+				SpendingTxs: []SpendingTx{
+					NewSpendingTx(ethcmn.BytesToHash([]byte{4}), SendTx, 1),
+					NewSpendingTx(ethcmn.BytesToHash([]byte{4}), DepositTx, 2),
+				},
+			},
+			expPass: false,
+		},
+		{
+			name: "public spending transaction",
+			genState: GenesisState{
+				ChainConfig: DefaultChainConfig(),
+				Params:      DefaultParams(),
+				SpendingTxs: []SpendingTx{NewSpendingTx(ethcmn.BytesToHash([]byte{4}), PublicTx, 1)},
+			},
+			expPass: false,
+		},
+		{
+			name: "duplicated commitment",
+			genState: GenesisState{
+				ChainConfig: DefaultChainConfig(),
+				Params:      DefaultParams(),
+				Commitments: []ethcmn.Hash{ethcmn.BytesToHash([]byte{1}), ethcmn.BytesToHash([]byte{1})},
+			},
+			expPass: false,
+		},
+		{
+			name: "commitments of several trees",
+			genState: GenesisState{
+				ChainConfig: DefaultChainConfig(),
+				Params:      DefaultParams(),
+				Commitments: func() []ethcmn.Hash {
+					cmts := make([]ethcmn.Hash, zktx.MerkleTreeLeaves+1)
+					for i := range cmts {
+						cmts[i] = ethcmn.BytesToHash([]byte{byte(i + 1)})
+					}
+					return cmts
+				}(),
+				CommitmentRoots: []CommitmentRoot{
+					NewCommitmentRoot(ethcmn.BytesToHash([]byte{1}), 1, zktx.MerkleTreeLeaves),
+					NewCommitmentRoot(ethcmn.BytesToHash([]byte{2}), 1, zktx.MerkleTreeLeaves+1),
+				},
+			},
+			expPass: true,
+		},
+		{
+			name: "commitment roots not sorted by size",
+			genState: GenesisState{
+				ChainConfig: DefaultChainConfig(),
+				Params:      DefaultParams(),
+				Commitments: []ethcmn.Hash{ethcmn.BytesToHash([]byte{1}), ethcmn.BytesToHash([]byte{2})},
+				CommitmentRoots: []CommitmentRoot{
+					NewCommitmentRoot(ethcmn.BytesToHash([]byte{1}), 1, 2),
+					NewCommitmentRoot(ethcmn.BytesToHash([]byte{2}), 2, 1),
+				},
+			},
+			expPass: false,
+		},
+		{
+			name: "commitment root beyond commitments",
+			genState: GenesisState{
+				ChainConfig:     DefaultChainConfig(),
+				Params:          DefaultParams(),
+				Commitments:     []ethcmn.Hash{ethcmn.BytesToHash([]byte{1})},
+				CommitmentRoots: []CommitmentRoot{NewCommitmentRoot(ethcmn.BytesToHash([]byte{2}), 1, 2)},
+			},
+			expPass: false,
+		},
+		{
+			name: "invalid commitment root",
+			genState: GenesisState{
+				ChainConfig:     DefaultChainConfig(),
+				Params:          DefaultParams(),
+				Commitments:     []ethcmn.Hash{ethcmn.BytesToHash([]byte{1})},
+				CommitmentRoots: []CommitmentRoot{NewCommitmentRoot(ethcmn.Hash{}, 1, 1)},
+			},
+			expPass: false,
+		},
+		{
+			name: "negative shielded supply",
+			genState: GenesisState{
+				ChainConfig:    DefaultChainConfig(),
+				Params:         DefaultParams(),
+				ShieldedSupply: big.NewInt(-1),
+			},
+			expPass: false,
+		},
 		{
 			name: "invalid params",
 			genState: GenesisState{
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"math/big"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
@@ -173,6 +175,10 @@ type (
 		prev uint64
 	}
 
+	shieldedSupplyChange struct {
+		prev *big.Int
+	}
+
 	addLogChange struct {
 		txhash ethcmn.Hash
 	}
@@ -285,6 +291,14 @@ func (ch refundChange) revert(s *CommitStateDB) {
 	s.refund = ch.prev
 }
 
+func (ch shieldedSupplyChange) revert(s *CommitStateDB) {
+	s.shieldedSupplyDelta = ch.prev
+}
+
+func (ch shieldedSupplyChange) dirtied() *ethcmn.Address {
+	return nil
+}
+
 func (ch refundChange) dirtied() *ethcmn.Address {
 	return nil
 }
@@ -21,12 +21,22 @@ const (
 
 // KVStore key prefixes
 var (
-	KeyPrefixBlockHash   = []byte{0x01}
-	KeyPrefixBloom       = []byte{0x02}
-	KeyPrefixLogs        = []byte{0x03}
-	KeyPrefixCode        = []byte{0x04}
-	KeyPrefixStorage     = []byte{0x05}
-	KeyPrefixChainConfig = []byte{0x06}
+	KeyPrefixBlockHash      = []byte{0x01}
+	KeyPrefixBloom          = []byte{0x02}
+	KeyPrefixLogs           = []byte{0x03}
+	KeyPrefixCode           = []byte{0x04}
+	KeyPrefixStorage        = []byte{0x05}
+	KeyPrefixChainConfig    = []byte{0x06}
+	KeyPrefixNullifier      = []byte{0x07}
+	KeyPrefixShieldedSupply = []byte{0x08}
+	KeyPrefixMerkleNode     = []byte{0x09}
+	KeyPrefixCommitment     = []byte{0x0a}
+	KeyPrefixCommitmentRoot = []byte{0x0b}
+	KeyPrefixRootHistory    = []byte{0x0c}
+	KeyPrefixTreeSize       = []byte{0x0d}
+	KeyPrefixCommitmentTx   = []byte{0x0e}
+	KeyPrefixNote           = []byte{0x0f}
+	KeyPrefixSpendingTx     = []byte{0x10}
 )
 
 // BloomKey defines the store key for a block Bloom
@@ -38,3 +48,15 @@ func BloomKey(height int64) []byte {
 func AddressStoragePrefix(address ethcmn.Address) []byte {
 	return append(KeyPrefixStorage, address.Bytes()...)
 }
+
+// AddressNotePrefix returns a prefix to iterate over the unspent notes of a
+// given owner.
+func AddressNotePrefix(owner ethcmn.Address) []byte {
+	return append(KeyPrefixNote, owner.Bytes()...)
+}
+
+// MerkleNodeKey defines the store key of the node of the commitment tree at the
+// given height and index
+func MerkleNodeKey(height int, index uint64) []byte {
+	return append([]byte{byte(height)}, sdk.Uint64ToBigEndian(index)...)
+}
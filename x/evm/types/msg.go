@@ -1,6 +1,7 @@
 package types
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
@@ -118,6 +119,9 @@ func (msg MsgEthermint) To() *ethcmn.Address {
 type MsgEthereumTx struct {
 	Data TxData
 
+	// Shielded is the payload of a shielded transaction, nil for a public one
+	Shielded ShieldedTxData
+
 	// caches
 	size atomic.Value
 	from atomic.Value
@@ -155,7 +159,6 @@ func newMsgEthereumTx(
 	}
 
 	txData := TxData{
-		Code:         PublicTx,
 		AccountNonce: nonce,
 		Recipient:    to,
 		Payload:      payload,
@@ -165,11 +168,6 @@ func newMsgEthereumTx(
 		V:            new(big.Int),
 		R:            new(big.Int),
 		S:            new(big.Int),
-		ZKSN:         &ethcmn.Hash{},
-		ZKSNS:        &ethcmn.Hash{},
-		ZKCMT:        &ethcmn.Hash{},
-		ZKCMTS:       &ethcmn.Hash{},
-		ZKAdrress:    &ethcmn.Address{},
 	}
 
 	if amount != nil {
@@ -183,12 +181,21 @@ func newMsgEthereumTx(
 }
 
 func (msg MsgEthereumTx) String() string {
-	return msg.Data.String()
+	if msg.Shielded == nil {
+		return msg.Data.String()
+	}
+	return fmt.Sprintf("%s %s", msg.Data.String(), shieldedString(msg.Shielded))
 }
 
+// Code returns the type of the transaction, PublicTx for a legacy Ethereum
+// transaction.
 func (msg MsgEthereumTx) Code() uint8 {
-	return msg.Data.Code
+	if msg.Shielded == nil {
+		return PublicTx
+	}
+	return msg.Shielded.TxType()
 }
+
 // Route returns the route value of an MsgEthereumTx.
 func (msg MsgEthereumTx) Route() string { return RouterKey }
 
@@ -247,37 +254,140 @@ func (msg MsgEthereumTx) GetSignBytes() []byte {
 }
 
 // RLPSignBytes returns the RLP hash of an Ethereum transaction message with a
-// given chainID used for signing.
+// given chainID used for signing. Public transactions are signed like EIP155
+// Ethereum transactions. Shielded transactions sign the hash of their type byte
+// followed by the RLP list of their Ethereum fields, all the zk fields of their
+// payload but the deposit signature, and the chainID.
 func (msg MsgEthereumTx) RLPSignBytes(chainID *big.Int) ethcmn.Hash {
-	return rlpHash([]interface{}{
+	if msg.Shielded == nil {
+		return rlpHash([]interface{}{
+			msg.Data.AccountNonce,
+			msg.Data.Price,
+			msg.Data.GasLimit,
+			msg.Data.Recipient,
+			msg.Data.Amount,
+			msg.Data.Payload,
+			chainID, uint(0), uint(0),
+		})
+	}
+
+	return prefixedRlpHash(msg.Shielded.TxType(), []interface{}{
 		msg.Data.AccountNonce,
 		msg.Data.Price,
 		msg.Data.GasLimit,
 		msg.Data.Recipient,
 		msg.Data.Amount,
 		msg.Data.Payload,
-		chainID, uint(0), uint(0),
+		msg.Shielded.signingData(),
+		chainID,
 	})
 }
 
-// EncodeRLP implements the rlp.Encoder interface.
+// EncodeRLP implements the rlp.Encoder interface. Public transactions are
+// encoded as legacy Ethereum transactions, and shielded ones as an RLP string
+// holding their typed envelope.
 func (msg *MsgEthereumTx) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &msg.Data)
+	if msg.Shielded == nil {
+		return rlp.Encode(w, &msg.Data)
+	}
+
+	bz, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, bz)
 }
 
 // DecodeRLP implements the rlp.Decoder interface.
 func (msg *MsgEthereumTx) DecodeRLP(s *rlp.Stream) error {
-	_, size, err := s.Kind()
+	kind, size, err := s.Kind()
 	if err != nil {
 		// return error if stream is too large
 		return err
 	}
 
-	if err := s.Decode(&msg.Data); err != nil {
+	switch kind {
+	case rlp.List:
+		if err := s.Decode(&msg.Data); err != nil {
+			return err
+		}
+
+		msg.Shielded = nil
+		msg.size.Store(ethcmn.StorageSize(rlp.ListSize(size)))
+		return nil
+	case rlp.String:
+		bz, err := s.Bytes()
+		if err != nil {
+			return err
+		}
+		return msg.unmarshalTyped(bz)
+	default:
+		return rlp.ErrExpectedList
+	}
+}
+
+// MarshalBinary returns the canonical encoding of the transaction: the RLP
+// encoding of public transactions, and the type byte followed by the RLP list
+// of the TxData and the payload of shielded ones.
+func (msg *MsgEthereumTx) MarshalBinary() ([]byte, error) {
+	if msg.Shielded == nil {
+		return rlp.EncodeToBytes(&msg.Data)
+	}
+
+	bz, err := rlp.EncodeToBytes([]interface{}{&msg.Data, msg.Shielded})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{msg.Shielded.TxType()}, bz...), nil
+}
+
+// UnmarshalBinary decodes the canonical encoding of a transaction, as returned
+// by MarshalBinary.
+func (msg *MsgEthereumTx) UnmarshalBinary(bz []byte) error {
+	// an RLP list starts above 0x7f, the type of a typed envelope below
+	if len(bz) > 0 && bz[0] > 0x7f {
+		var data TxData
+		if err := rlp.DecodeBytes(bz, &data); err != nil {
+			return err
+		}
+
+		msg.Data = data
+		msg.Shielded = nil
+		msg.size.Store(ethcmn.StorageSize(len(bz)))
+		return nil
+	}
+	return msg.unmarshalTyped(bz)
+}
+
+// unmarshalTyped decodes the typed envelope of a shielded transaction.
+func (msg *MsgEthereumTx) unmarshalTyped(bz []byte) error {
+	if len(bz) == 0 {
+		return errors.New("empty typed transaction bytes")
+	}
+
+	shielded, err := NewShieldedTxData(bz[0])
+	if err != nil {
 		return err
 	}
 
-	msg.size.Store(ethcmn.StorageSize(rlp.ListSize(size)))
+	var data TxData
+	s := rlp.NewStream(bytes.NewReader(bz[1:]), uint64(len(bz)-1))
+	if _, err := s.List(); err != nil {
+		return err
+	}
+	if err := s.Decode(&data); err != nil {
+		return err
+	}
+	if err := s.Decode(shielded); err != nil {
+		return err
+	}
+	if err := s.ListEnd(); err != nil {
+		return err
+	}
+
+	msg.Data = data
+	msg.Shielded = shielded
+	msg.size.Store(ethcmn.StorageSize(len(bz)))
 	return nil
 }
 
@@ -286,21 +396,53 @@ func (msg *MsgEthereumTx) DecodeRLP(s *rlp.Stream) error {
 // EIP155 standard. It mutates the transaction as it populates the V, R, S
 // fields of the Transaction's Signature.
 func (msg *MsgEthereumTx) Sign(chainID *big.Int, priv *ecdsa.PrivateKey) error {
+	v, r, s, err := msg.signatureValues(chainID, priv)
+	if err != nil {
+		return err
+	}
+
+	msg.Data.V = v
+	msg.Data.R = r
+	msg.Data.S = s
+	return nil
+}
+
+// SignDeposit signs a deposit transaction with the one-time private key of the
+// receiver, derived from the public key R of the send transaction. It signs the
+// same bytes as Sign and populates the V, R, S fields of the DepositTxData.
+func (msg *MsgEthereumTx) SignDeposit(chainID *big.Int, priv *ecdsa.PrivateKey) error {
+	deposit, ok := msg.Shielded.(*DepositTxData)
+	if !ok {
+		return errors.New("not a deposit transaction")
+	}
+
+	v, r, s, err := msg.signatureValues(chainID, priv)
+	if err != nil {
+		return err
+	}
+
+	deposit.V = v
+	deposit.R = r
+	deposit.S = s
+	return nil
+}
+
+// signatureValues signs the RLP hash of the transaction for the given chainID
+// and returns the EIP155 V, R, S values of the signature.
+func (msg *MsgEthereumTx) signatureValues(chainID *big.Int, priv *ecdsa.PrivateKey) (v, r, s *big.Int, err error) {
 	txHash := msg.RLPSignBytes(chainID)
 
 	sig, err := ethcrypto.Sign(txHash[:], priv)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
 
 	if len(sig) != 65 {
-		return fmt.Errorf("wrong size for signature: got %d, want 65", len(sig))
+		return nil, nil, nil, fmt.Errorf("wrong size for signature: got %d, want 65", len(sig))
 	}
 
-	r := new(big.Int).SetBytes(sig[:32])
-	s := new(big.Int).SetBytes(sig[32:64])
-
-	var v *big.Int
+	r = new(big.Int).SetBytes(sig[:32])
+	s = new(big.Int).SetBytes(sig[32:64])
 
 	if chainID.Sign() == 0 {
 		v = new(big.Int).SetBytes([]byte{sig[64] + 27})
@@ -311,10 +453,28 @@ func (msg *MsgEthereumTx) Sign(chainID *big.Int, priv *ecdsa.PrivateKey) error {
 		v.Add(v, chainIDMul)
 	}
 
-	msg.Data.V = v
-	msg.Data.R = r
-	msg.Data.S = s
-	return nil
+	return v, r, s, nil
+}
+
+// VerifyDepositSig attempts to verify the one-time key signature of a deposit
+// transaction for a given chainID. The address of the one-time key is returned
+// upon success or an error if recovery fails.
+func (msg *MsgEthereumTx) VerifyDepositSig(chainID *big.Int) (ethcmn.Address, error) {
+	deposit, ok := msg.Shielded.(*DepositTxData)
+	if !ok || deposit.V == nil || deposit.R == nil || deposit.S == nil {
+		return ethcmn.Address{}, errors.New("missing deposit signature")
+	}
+
+	// do not allow recovery for transactions with an unprotected chainID
+	if chainID.Sign() == 0 {
+		return ethcmn.Address{}, errors.New("chainID cannot be zero")
+	}
+
+	chainIDMul := new(big.Int).Mul(chainID, big.NewInt(2))
+	V := new(big.Int).Sub(deposit.V, chainIDMul)
+	V.Sub(V, big8)
+
+	return recoverEthSig(deposit.R, deposit.S, V, msg.RLPSignBytes(chainID))
 }
 
 // VerifySig attempts to verify a Transaction's signature for a given chainID.
@@ -486,150 +646,189 @@ func ExtractPKBAddress(signer ethtypes.Signer, msg *MsgEthereumTx) (ethcmn.Addre
 	return sender, nil
 }
 
-func (tx *MsgEthereumTx) ZKValue() uint64 {
-	return tx.Data.ZKValue
+// zkFields returns the zk fields of the payload of the transaction, none for a
+// public transaction.
+func (tx *MsgEthereumTx) zkFields() zkFields {
+	if tx.Shielded == nil {
+		return zkFields{}
+	}
+	return tx.Shielded.zkFields()
 }
 
-func (tx *MsgEthereumTx) SetZKValue(Value uint64) {
-	tx.Data.ZKValue = Value
+// mustField panics if the payload of the transaction doesn't have a zk field.
+func (tx *MsgEthereumTx) mustField(ok bool, name string) {
+	if !ok {
+		panic(fmt.Sprintf("transaction of type %d has no %s field", tx.TxCode(), name))
+	}
 }
 
-func (tx *MsgEthereumTx) TxCode() uint8 {
-	return tx.Data.Code
+// ZKValue returns the value minted or redeemed by the transaction.
+func (tx *MsgEthereumTx) ZKValue() uint64 {
+	if f := tx.zkFields(); f.value != nil {
+		return *f.value
+	}
+	return 0
 }
 
-func (tx *MsgEthereumTx) SetTxCode(code uint8) {
-	tx.Data.Code = code
+// SetZKValue sets the value minted or redeemed by the transaction.
+func (tx *MsgEthereumTx) SetZKValue(value uint64) {
+	f := tx.zkFields()
+	tx.mustField(f.value != nil, "value")
+	*f.value = value
 }
 
-//
-func (tx *MsgEthereumTx) SetTxRecepient(address *ethcmn.Address) {
-	tx.Data.Recipient = address
+// TxCode returns the type of the transaction.
+func (tx *MsgEthereumTx) TxCode() uint8 {
+	return tx.Code()
 }
 
-//
-func (tx *MsgEthereumTx) ZKAddress() *ethcmn.Address {
-	return tx.Data.ZKAdrress
-}
+// SetTxCode sets the type of the transaction, replacing its payload with an
+// empty payload of the type. It panics on an unsupported type.
+func (tx *MsgEthereumTx) SetTxCode(code uint8) {
+	if code == PublicTx {
+		tx.Shielded = nil
+		return
+	}
 
-//
-func (tx *MsgEthereumTx) SetZKAddress(address *ethcmn.Address) {
-	tx.Data.ZKAdrress = address
+	shielded, err := NewShieldedTxData(code)
+	if err != nil {
+		panic(err)
+	}
+	tx.Shielded = shielded
 }
 
 //
-func (tx *MsgEthereumTx) SetZKNounce(nounce uint64) {
-	tx.Data.ZKNounce = nounce
+func (tx *MsgEthereumTx) SetTxRecepient(address *ethcmn.Address) {
+	tx.Data.Recipient = address
 }
 
-//
+// ZKSN returns the serial number of the note spent by the transaction.
 func (tx *MsgEthereumTx) ZKSN() *ethcmn.Hash {
-	return tx.Data.ZKSN
+	return tx.zkFields().sn
 }
 
-//
+// SetZKSN sets the serial number of the note spent by the transaction.
 func (tx *MsgEthereumTx) SetZKSN(hash *ethcmn.Hash) {
-	tx.Data.ZKSN = hash
+	f := tx.zkFields()
+	tx.mustField(f.sn != nil, "sn")
+	*f.sn = hashValue(hash)
 }
 
-//
+// ZKSNS returns the serial number of the send commitment claimed by a deposit.
 func (tx *MsgEthereumTx) ZKSNS() *ethcmn.Hash {
-	return tx.Data.ZKSNS
+	return tx.zkFields().sns
 }
 
-//
+// SetZKSNS sets the serial number of the send commitment claimed by a deposit.
 func (tx *MsgEthereumTx) SetZKSNS(hash *ethcmn.Hash) {
-	tx.Data.ZKSNS = hash
+	f := tx.zkFields()
+	tx.mustField(f.sns != nil, "sns")
+	*f.sns = hashValue(hash)
 }
 
-//
-func (tx *MsgEthereumTx) R() (*big.Int, *big.Int) {
-	return tx.Data.X, tx.Data.Y
-}
-
-//
+// X returns the X coordinate of the one-time public key of a send or deposit.
 func (tx *MsgEthereumTx) X() *big.Int {
-	return tx.Data.X
-
+	if f := tx.zkFields(); f.x != nil {
+		return *f.x
+	}
+	return nil
 }
 
-//
+// Y returns the Y coordinate of the one-time public key of a send or deposit.
 func (tx *MsgEthereumTx) Y() *big.Int {
-	return tx.Data.Y
-
-}
-
-//
-func (tx *MsgEthereumTx) PubKey() (*big.Int, *big.Int) {
-	return tx.Data.X, tx.Data.Y
+	if f := tx.zkFields(); f.y != nil {
+		return *f.y
+	}
+	return nil
 }
 
-//
+// SetPubKey sets the one-time public key of a send or deposit.
 func (tx *MsgEthereumTx) SetPubKey(x *big.Int, y *big.Int) {
-	tx.Data.X = x
-	tx.Data.Y = y
+	f := tx.zkFields()
+	tx.mustField(f.x != nil, "one-time public key")
+	*f.x = x
+	*f.y = y
 }
 
-//
+// ZKCMT returns the commitment of the note created by the transaction.
 func (tx *MsgEthereumTx) ZKCMT() *ethcmn.Hash {
-	return tx.Data.ZKCMT
+	return tx.zkFields().cmt
 }
 
-//
-func (tx *MsgEthereumTx) ZKCMTS() *ethcmn.Hash {
-	return tx.Data.ZKCMTS
+// SetZKCMT sets the commitment of the note created by the transaction.
+func (tx *MsgEthereumTx) SetZKCMT(hash *ethcmn.Hash) {
+	f := tx.zkFields()
+	tx.mustField(f.cmt != nil, "cmt")
+	*f.cmt = hashValue(hash)
 }
 
-//
-func (tx *MsgEthereumTx) SetZKCMT(hash *ethcmn.Hash) {
-	tx.Data.ZKCMT = hash
+// ZKCMTS returns the send commitment created by a send.
+func (tx *MsgEthereumTx) ZKCMTS() *ethcmn.Hash {
+	return tx.zkFields().cmts
 }
 
-//
+// SetZKCMTS sets the send commitment created by a send.
 func (tx *MsgEthereumTx) SetZKCMTS(hash *ethcmn.Hash) {
-	tx.Data.ZKCMTS = hash
+	f := tx.zkFields()
+	tx.mustField(f.cmts != nil, "cmts")
+	*f.cmts = hashValue(hash)
 }
 
-//
+// ZKCMTOld returns the commitment of the note spent by the transaction.
+func (tx *MsgEthereumTx) ZKCMTOld() *ethcmn.Hash {
+	return tx.zkFields().cmtOld
+}
+
+// SetZKCMTOld sets the commitment of the note spent by the transaction.
+func (tx *MsgEthereumTx) SetZKCMTOld(hash *ethcmn.Hash) {
+	f := tx.zkFields()
+	tx.mustField(f.cmtOld != nil, "cmtOld")
+	*f.cmtOld = hashValue(hash)
+}
+
+// AUX returns the encrypted value of a send.
 func (tx *MsgEthereumTx) AUX() []byte {
-	return tx.Data.AUX
+	if f := tx.zkFields(); f.aux != nil {
+		return *f.aux
+	}
+	return nil
 }
 
-//
-func (tx *MsgEthereumTx) SetAUX(AUX []byte) {
-	tx.Data.AUX = make([]byte, len(AUX))
-	copy(tx.Data.AUX[:], AUX[:])
+// SetAUX sets the encrypted value of a send.
+func (tx *MsgEthereumTx) SetAUX(aux []byte) {
+	f := tx.zkFields()
+	tx.mustField(f.aux != nil, "aux")
+	*f.aux = ethcmn.CopyBytes(aux)
 }
 
-//
+// ZKProof returns the zk-SNARK proof of the transaction.
 func (tx *MsgEthereumTx) ZKProof() []byte {
-	return tx.Data.ZKProof
+	if f := tx.zkFields(); f.proof != nil {
+		return *f.proof
+	}
+	return nil
 }
 
-//
+// SetZKProof sets the zk-SNARK proof of the transaction.
 func (tx *MsgEthereumTx) SetZKProof(proof []byte) {
-	tx.Data.ZKProof = make([]byte, len(proof))
-	copy(tx.Data.ZKProof[:], proof[:])
+	f := tx.zkFields()
+	tx.mustField(f.proof != nil, "proof")
+	*f.proof = ethcmn.CopyBytes(proof)
 }
 
-//
+// RTcmt returns the commitment root a deposit is proven against.
 func (tx *MsgEthereumTx) RTcmt() ethcmn.Hash {
-	return tx.Data.RTcmt
+	if f := tx.zkFields(); f.rtcmt != nil {
+		return *f.rtcmt
+	}
+	return ethcmn.Hash{}
 }
 
-//
+// SetRTcmt sets the commitment root a deposit is proven against.
 func (tx *MsgEthereumTx) SetRTcmt(rtcmt ethcmn.Hash) {
-	tx.Data.RTcmt = rtcmt
-}
-
-func (tx *MsgEthereumTx) SetCMTBlocks(blocks []uint64) {
-	tx.Data.CMTBlock = make([]uint64, len(blocks))
-	copy(tx.Data.CMTBlock, blocks)
-}
-
-func (tx *MsgEthereumTx) CMTBlocks() []uint64 {
-	return tx.Data.CMTBlock
-
+	f := tx.zkFields()
+	tx.mustField(f.rtcmt != nil, "rtcmt")
+	*f.rtcmt = rtcmt
 }
 
 func (tx *MsgEthereumTx) SetPrice(price *big.Int) {
@@ -637,4 +836,11 @@ func (tx *MsgEthereumTx) SetPrice(price *big.Int) {
 }
 func (tx *MsgEthereumTx) SetValue(value *big.Int) {
 	tx.Data.Amount = value
-}
\ No newline at end of file
+}
+
+func hashValue(hash *ethcmn.Hash) ethcmn.Hash {
+	if hash == nil {
+		return ethcmn.Hash{}
+	}
+	return *hash
+}
@@ -160,6 +160,38 @@ func TestMsgEthereumTxRLPDecode(t *testing.T) {
 	require.Equal(t, expectedMsg.Data, msg.Data)
 }
 
+func TestMsgEthereumTxLegacyEncoding(t *testing.T) {
+	chainID := big.NewInt(3)
+	priv, _ := ethsecp256k1.GenerateKey()
+	addr := ethcmn.BytesToAddress([]byte("test_address"))
+
+	// public transactions are encoded and signed like go-ethereum transactions
+	msg := NewMsgEthereumTx(1, &addr, big.NewInt(10), 100000, big.NewInt(2), []byte("test"))
+	require.NoError(t, msg.Sign(chainID, priv.ToECDSA()))
+	require.Equal(t, PublicTx, msg.Code())
+
+	signer := ethtypes.NewEIP155Signer(chainID)
+	ethTx, err := ethtypes.SignTx(
+		ethtypes.NewTransaction(1, addr, big.NewInt(10), 100000, big.NewInt(2), []byte("test")), signer, priv.ToECDSA(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, signer.Hash(ethTx), msg.RLPSignBytes(chainID))
+
+	expected, err := rlp.EncodeToBytes(ethTx)
+	require.NoError(t, err)
+	raw, err := rlp.EncodeToBytes(&msg)
+	require.NoError(t, err)
+	require.Equal(t, expected, raw)
+	bz, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, expected, bz)
+
+	var decoded MsgEthereumTx
+	require.NoError(t, decoded.UnmarshalBinary(expected))
+	require.Nil(t, decoded.Shielded)
+	require.Equal(t, msg.Data, decoded.Data)
+}
+
 func TestMsgEthereumTxSig(t *testing.T) {
 	chainID := big.NewInt(3)
 
@@ -188,6 +220,112 @@ func TestMsgEthereumTxSig(t *testing.T) {
 	require.Equal(t, ethcmn.Address{}, signer)
 }
 
+func TestMsgEthereumTxShieldedSig(t *testing.T) {
+	chainID := big.NewInt(3)
+	priv, _ := ethsecp256k1.GenerateKey()
+	addr := ethcmn.BytesToAddress(priv.PubKey().Address().Bytes())
+	other := ethcmn.BytesToHash([]byte("other"))
+
+	tamperings := []struct {
+		msg    string
+		tamper func(msg *MsgEthereumTx)
+	}{
+		{"sn", func(msg *MsgEthereumTx) { msg.SetZKSN(&other) }},
+		{"spent note", func(msg *MsgEthereumTx) { msg.SetZKCMTOld(&other) }},
+		{"cmt", func(msg *MsgEthereumTx) { msg.SetZKCMT(&other) }},
+		{"proof", func(msg *MsgEthereumTx) { msg.SetZKProof([]byte("forged")) }},
+	}
+
+	for _, msg := range newTestShieldedTxs() {
+		msg := msg
+		if msg.Code() == DepositTx {
+			// the one-time key signature isn't covered by the sender's one
+			require.NoError(t, msg.SignDeposit(chainID, priv.ToECDSA()))
+		}
+		require.NoError(t, msg.Sign(chainID, priv.ToECDSA()))
+		require.NotEqual(t, msg.RLPSignBytes(chainID), msg.RLPSignBytes(big.NewInt(4)))
+
+		bz, err := msg.MarshalBinary()
+		require.NoError(t, err)
+
+		var signed MsgEthereumTx
+		require.NoError(t, signed.UnmarshalBinary(bz))
+		signer, err := signed.VerifySig(chainID)
+		require.NoError(t, err)
+		require.Equal(t, addr, signer)
+
+		signer, err = signed.VerifySig(big.NewInt(4))
+		require.True(t, err != nil || signer != addr)
+
+		// a transaction altered after signing recovers another sender, if any
+		for _, tc := range tamperings {
+			var tampered MsgEthereumTx
+			require.NoError(t, tampered.UnmarshalBinary(bz))
+			tc.tamper(&tampered)
+
+			signer, err := tampered.VerifySig(chainID)
+			require.True(t, err != nil || signer != addr, "%d: %s", msg.Code(), tc.msg)
+		}
+
+		switch msg.Code() {
+		case MintTx:
+			// the same fields under another type
+			var tampered MsgEthereumTx
+			require.NoError(t, tampered.UnmarshalBinary(bz))
+			redeem := RedeemTxData(*tampered.Shielded.(*MintTxData))
+			tampered.Shielded = &redeem
+			signer, err := tampered.VerifySig(chainID)
+			require.True(t, err != nil || signer != addr, "mint: type")
+			fallthrough
+		case RedeemTx:
+			var tampered MsgEthereumTx
+			require.NoError(t, tampered.UnmarshalBinary(bz))
+			tampered.SetZKValue(1000)
+			signer, err := tampered.VerifySig(chainID)
+			require.True(t, err != nil || signer != addr, "%d: value", msg.Code())
+		case SendTx:
+			var tampered MsgEthereumTx
+			require.NoError(t, tampered.UnmarshalBinary(bz))
+			tampered.SetAUX([]byte("forged"))
+			signer, err := tampered.VerifySig(chainID)
+			require.True(t, err != nil || signer != addr, "send: aux")
+		}
+	}
+}
+
+func TestMsgEthereumTxDepositSig(t *testing.T) {
+	chainID := big.NewInt(3)
+
+	priv1, _ := ethsecp256k1.GenerateKey()
+	priv2, _ := ethsecp256k1.GenerateKey()
+	addr1 := ethcmn.BytesToAddress(priv1.PubKey().Address().Bytes())
+	addr2 := ethcmn.BytesToAddress(priv2.PubKey().Address().Bytes())
+
+	// require a missing deposit signature to fail validation
+	msg := NewMsgEthereumTx(0, &addr1, nil, 100000, nil, []byte("test"))
+	require.Error(t, msg.SignDeposit(chainID, priv2.ToECDSA()))
+	msg.SetTxCode(DepositTx)
+	_, err := msg.VerifyDepositSig(chainID)
+	require.Error(t, err)
+
+	// require the deposit signature to be independent of the sender's one
+	require.NoError(t, msg.SignDeposit(chainID, priv2.ToECDSA()))
+	require.NoError(t, msg.Sign(chainID, priv1.ToECDSA()))
+
+	signer, err := msg.VerifySig(chainID)
+	require.NoError(t, err)
+	require.Equal(t, addr1, signer)
+
+	signer, err = msg.VerifyDepositSig(chainID)
+	require.NoError(t, err)
+	require.Equal(t, addr2, signer)
+
+	// require invalid chain ID fail validation
+	signer, err = msg.VerifyDepositSig(big.NewInt(4))
+	require.Error(t, err)
+	require.Equal(t, ethcmn.Address{}, signer)
+}
+
 func TestMarshalAndUnmarshalLogs(t *testing.T) {
 	var cdc = codec.New()
 
@@ -0,0 +1,35 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// Note defines an unspent shielded note of an account, identified by its
+// commitment. Every account also holds the zero valued initial note, which is
+// never stored. It is used for import/export of the unspent notes.
+type Note struct {
+	Owner ethcmn.Address `json:"owner"`
+	CMT   ethcmn.Hash    `json:"cmt"`
+}
+
+// NewNote creates a new Note instance.
+func NewNote(owner ethcmn.Address, cmt ethcmn.Hash) Note {
+	return Note{
+		Owner: owner,
+		CMT:   cmt,
+	}
+}
+
+// Validate performs a basic validation of the Note fields.
+func (n Note) Validate() error {
+	if bytes.Equal(n.Owner.Bytes(), ethcmn.Address{}.Bytes()) {
+		return errors.New("owner cannot be the zero address")
+	}
+	if bytes.Equal(n.CMT.Bytes(), ethcmn.Hash{}.Bytes()) {
+		return errors.New("commitment cannot be empty")
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Nullifier defines the serial number (SN) of a spent shielded note together
+// with the height of the block and the hash of the transaction it was spent
+// in. It is used for import/export of the nullifier set.
+type Nullifier struct {
+	SN     ethcmn.Hash `json:"sn"`
+	Height int64       `json:"height"`
+	TxHash ethcmn.Hash `json:"tx_hash"`
+}
+
+// NewNullifier creates a new Nullifier instance.
+func NewNullifier(sn ethcmn.Hash, height int64, txHash ethcmn.Hash) Nullifier {
+	return Nullifier{
+		SN:     sn,
+		Height: height,
+		TxHash: txHash,
+	}
+}
+
+// Encode returns the store value of the nullifier: the big endian height
+// followed by the transaction hash.
+func (n Nullifier) Encode() []byte {
+	return append(sdk.Uint64ToBigEndian(uint64(n.Height)), n.TxHash.Bytes()...)
+}
+
+// DecodeNullifier decodes the store value of the nullifier with the given
+// serial number.
+func DecodeNullifier(sn ethcmn.Hash, bz []byte) Nullifier {
+	return NewNullifier(
+		sn,
+		int64(binary.BigEndian.Uint64(bz[:8])),
+		ethcmn.BytesToHash(bz[8:]),
+	)
+}
+
+// Validate performs a basic validation of the Nullifier fields.
+func (n Nullifier) Validate() error {
+	if bytes.Equal(n.SN.Bytes(), ethcmn.Hash{}.Bytes()) {
+		return errors.New("serial number cannot be empty")
+	}
+	if n.Height < 0 {
+		return fmt.Errorf("height cannot be negative %d", n.Height)
+	}
+	if bytes.Equal(n.TxHash.Bytes(), ethcmn.Hash{}.Bytes()) {
+		return errors.New("transaction hash cannot be empty")
+	}
+	return nil
+}
+
+// SpendingTx records a processed shielded transaction together with the number
+// of entries it added to the nullifier set. It is used by the nullifier
+// invariant and for import/export.
+type SpendingTx struct {
+	TxHash     ethcmn.Hash `json:"tx_hash"`
+	TxCode     uint8       `json:"tx_code"`
+	Nullifiers uint32      `json:"nullifiers"`
+}
+
+// NewSpendingTx creates a new SpendingTx instance.
+func NewSpendingTx(txHash ethcmn.Hash, txCode uint8, nullifiers uint32) SpendingTx {
+	return SpendingTx{
+		TxHash:     txHash,
+		TxCode:     txCode,
+		Nullifiers: nullifiers,
+	}
+}
+
+// Encode returns the store value of the spending transaction: its code followed
+// by the big endian number of nullifiers.
+func (tx SpendingTx) Encode() []byte {
+	bz := make([]byte, 5)
+	bz[0] = tx.TxCode
+	binary.BigEndian.PutUint32(bz[1:], tx.Nullifiers)
+	return bz
+}
+
+// DecodeSpendingTx decodes the store value of the spending transaction with the
+// given hash.
+func DecodeSpendingTx(txHash ethcmn.Hash, bz []byte) SpendingTx {
+	return NewSpendingTx(txHash, bz[0], binary.BigEndian.Uint32(bz[1:5]))
+}
+
+// Validate performs a basic validation of the SpendingTx fields.
+func (tx SpendingTx) Validate() error {
+	if bytes.Equal(tx.TxHash.Bytes(), ethcmn.Hash{}.Bytes()) {
+		return errors.New("transaction hash cannot be empty")
+	}
+	if _, err := NewShieldedTxData(tx.TxCode); err != nil {
+		return err
+	}
+	if tx.Nullifiers == 0 {
+		return errors.New("spending transaction without nullifier")
+	}
+	return nil
+}
+
+// DepositNullifier returns the entry of the nullifier set recording that the
+// send commitment addressed to the given one-time key has been deposited. The
+// SNS of a deposit depends on the key of the note it is deposited into, so the
+// one-time key, which the deposit reveals and signs with, nullifies the send
+// commitment instead.
+func DepositNullifier(key *ecdsa.PublicKey) ethcmn.Hash {
+	return ethcrypto.Keccak256Hash([]byte("deposit"), ethcrypto.FromECDSAPub(key))
+}
+
+// ParseSN parses a hex encoded 32 byte serial number, with or without the 0x
+// prefix.
+func ParseSN(s string) (ethcmn.Hash, error) {
+	return parseHash(s, "serial number")
+}
+
+// ParseCMTS parses a hex encoded 32 byte send commitment, with or without the
+// 0x prefix.
+func ParseCMTS(s string) (ethcmn.Hash, error) {
+	return parseHash(s, "send commitment")
+}
+
+func parseHash(s, name string) (ethcmn.Hash, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		s = "0x" + s
+	}
+
+	bz, err := hexutil.Decode(s)
+	if err != nil {
+		return ethcmn.Hash{}, fmt.Errorf("invalid %s %s: %w", name, s, err)
+	}
+	if len(bz) != ethcmn.HashLength {
+		return ethcmn.Hash{}, fmt.Errorf("invalid %s length %d, expected %d", name, len(bz), ethcmn.HashLength)
+	}
+	return ethcmn.BytesToHash(bz), nil
+}
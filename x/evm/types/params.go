@@ -14,11 +14,20 @@ import (
 const (
 	// DefaultParamspace for params keeper
 	DefaultParamspace = ModuleName
+
+	// DefaultProofGas is the default gas charged for verifying the zk-SNARK
+	// proof of a shielded transaction, about the cost of the four pairings of a
+	// Groth16 verification on Ethereum (EIP-1108)
+	DefaultProofGas uint64 = 200000
 )
 
 // Parameter keys
 var (
-	ParamStoreKeyEVMDenom = []byte("EVMDenom")
+	ParamStoreKeyEVMDenom        = []byte("EVMDenom")
+	ParamStoreKeyMintProofGas    = []byte("MintProofGas")
+	ParamStoreKeySendProofGas    = []byte("SendProofGas")
+	ParamStoreKeyDepositProofGas = []byte("DepositProofGas")
+	ParamStoreKeyRedeemProofGas  = []byte("RedeemProofGas")
 )
 
 // ParamKeyTable returns the parameter key table.
@@ -29,19 +38,49 @@ func ParamKeyTable() params.KeyTable {
 // Params defines the EVM module parameters
 type Params struct {
 	EvmDenom string `json:"evm_denom" yaml:"evm_denom"`
+	// gas charged for verifying the proof of each shielded transaction circuit
+	MintProofGas    uint64 `json:"mint_proof_gas" yaml:"mint_proof_gas"`
+	SendProofGas    uint64 `json:"send_proof_gas" yaml:"send_proof_gas"`
+	DepositProofGas uint64 `json:"deposit_proof_gas" yaml:"deposit_proof_gas"`
+	RedeemProofGas  uint64 `json:"redeem_proof_gas" yaml:"redeem_proof_gas"`
 }
 
 // NewParams creates a new Params instance
-func NewParams(evmDenom string) Params {
+func NewParams(evmDenom string, mintProofGas, sendProofGas, depositProofGas, redeemProofGas uint64) Params {
 	return Params{
-		EvmDenom: evmDenom,
+		EvmDenom:        evmDenom,
+		MintProofGas:    mintProofGas,
+		SendProofGas:    sendProofGas,
+		DepositProofGas: depositProofGas,
+		RedeemProofGas:  redeemProofGas,
 	}
 }
 
 // DefaultParams returns default evm parameters
 func DefaultParams() Params {
 	return Params{
-		EvmDenom: ethermint.AttoPhoton,
+		EvmDenom:        ethermint.AttoPhoton,
+		MintProofGas:    DefaultProofGas,
+		SendProofGas:    DefaultProofGas,
+		DepositProofGas: DefaultProofGas,
+		RedeemProofGas:  DefaultProofGas,
+	}
+}
+
+// ProofGas returns the gas charged for verifying the proof of a shielded
+// transaction with the given code, 0 for public transactions.
+func (p Params) ProofGas(code uint8) uint64 {
+	switch code {
+	case MintTx:
+		return p.MintProofGas
+	case SendTx:
+		return p.SendProofGas
+	case DepositTx:
+		return p.DepositProofGas
+	case RedeemTx:
+		return p.RedeemProofGas
+	default:
+		return 0
 	}
 }
 
@@ -55,6 +94,10 @@ func (p Params) String() string {
 func (p *Params) ParamSetPairs() params.ParamSetPairs {
 	return params.ParamSetPairs{
 		params.NewParamSetPair(ParamStoreKeyEVMDenom, &p.EvmDenom, validateEVMDenom),
+		params.NewParamSetPair(ParamStoreKeyMintProofGas, &p.MintProofGas, validateProofGas),
+		params.NewParamSetPair(ParamStoreKeySendProofGas, &p.SendProofGas, validateProofGas),
+		params.NewParamSetPair(ParamStoreKeyDepositProofGas, &p.DepositProofGas, validateProofGas),
+		params.NewParamSetPair(ParamStoreKeyRedeemProofGas, &p.RedeemProofGas, validateProofGas),
 	}
 }
 
@@ -71,3 +114,11 @@ func validateEVMDenom(i interface{}) error {
 
 	return sdk.ValidateDenom(denom)
 }
+
+func validateProofGas(i interface{}) error {
+	if _, ok := i.(uint64); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return nil
+}
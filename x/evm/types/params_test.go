@@ -15,7 +15,7 @@ func TestParamsValidate(t *testing.T) {
 		{"default", DefaultParams(), false},
 		{
 			"valid",
-			NewParams("ara"),
+			NewParams("ara", 1, 2, 3, 4),
 			false,
 		},
 		{
@@ -45,8 +45,25 @@ func TestParamsValidate(t *testing.T) {
 
 func TestParamsValidatePriv(t *testing.T) {
 	require.Error(t, validateEVMDenom(false))
+	require.Error(t, validateProofGas(int64(1)))
+	require.NoError(t, validateProofGas(uint64(1)))
+}
+
+func TestParamsProofGas(t *testing.T) {
+	params := NewParams("ara", 1, 2, 3, 4)
+	require.Equal(t, uint64(0), params.ProofGas(PublicTx))
+	require.Equal(t, uint64(1), params.ProofGas(MintTx))
+	require.Equal(t, uint64(2), params.ProofGas(SendTx))
+	require.Equal(t, uint64(3), params.ProofGas(DepositTx))
+	require.Equal(t, uint64(4), params.ProofGas(RedeemTx))
+	require.Equal(t, uint64(0), params.ProofGas(UpdateTx))
 }
 
 func TestParams_String(t *testing.T) {
-	require.Equal(t, "evm_denom: aphoton\n", DefaultParams().String())
+	require.Equal(t, `evm_denom: aphoton
+mint_proof_gas: 200000
+send_proof_gas: 200000
+deposit_proof_gas: 200000
+redeem_proof_gas: 200000
+`, DefaultParams().String())
 }
@@ -2,6 +2,8 @@ package types
 
 import (
 	"fmt"
+	"strings"
+
 	ethcmn "github.com/ethereum/go-ethereum/common"
 
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -21,7 +23,10 @@ const (
 	QueryLogs            = "logs"
 	QueryAccount         = "account"
 	QueryExportAccount   = "exportAccount"
-	QueryResSN              = "SN"
+	QueryResSN           = "SN"
+	QueryCommitmentProof = "commitmentProof"
+	QueryNotes           = "notes"
+	QueryParameters      = "parameters"
 )
 
 // QueryResProtocolVersion is response type for protocol version query
@@ -104,10 +109,51 @@ type QueryResAccount struct {
 }
 
 type QueryResExportAccount = GenesisAccount
+
+// QuerySN is response type for serial number queries
 type QuerySN struct {
-	sn *ethcmn.Hash
+	SN     ethcmn.Hash `json:"sn"`
+	Spent  bool        `json:"spent"`
+	Height int64       `json:"height"`
+}
+
+func (q QuerySN) String() string {
+	if !q.Spent {
+		return fmt.Sprintf("sn=%s spent=false", q.SN.Hex())
+	}
+	return fmt.Sprintf("sn=%s spent=true height=%d", q.SN.Hex(), q.Height)
+}
+
+// QueryResNotes is response type for queries of the commitments of the unspent
+// shielded notes of an account, without the initial note
+type QueryResNotes struct {
+	Address ethcmn.Address `json:"address"`
+	Notes   []ethcmn.Hash  `json:"notes"`
 }
 
-func(q QuerySN) String() string  {
-	return q.sn.Hex()
-}
\ No newline at end of file
+func (q QueryResNotes) String() string {
+	notes := make([]string, len(q.Notes))
+	for i, cmt := range q.Notes {
+		notes[i] = cmt.Hex()
+	}
+	return fmt.Sprintf("address=%s notes=[%s]", q.Address.Hex(), strings.Join(notes, ", "))
+}
+
+// QueryResCommitmentProof is response type for send commitment authentication
+// path queries. Tree is the commitment tree holding the commitment, and Index,
+// Leaves and Size its leaf index, commitments in leaf order and number of
+// commitments within that tree. The deposit circuit rebuilds Root from Leaves.
+type QueryResCommitmentProof struct {
+	CMTS   ethcmn.Hash   `json:"cmts"`
+	Tree   uint64        `json:"tree"`
+	Index  uint64        `json:"index"`
+	Path   []ethcmn.Hash `json:"path"`
+	Leaves []ethcmn.Hash `json:"leaves"`
+	Root   ethcmn.Hash   `json:"root"`
+	Size   uint64        `json:"size"`
+	TxHash ethcmn.Hash   `json:"tx_hash"`
+}
+
+func (q QueryResCommitmentProof) String() string {
+	return fmt.Sprintf("cmts=%s tree=%d index=%d root=%s size=%d", q.CMTS.Hex(), q.Tree, q.Index, q.Root.Hex(), q.Size)
+}
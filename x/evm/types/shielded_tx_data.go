@@ -0,0 +1,396 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	_ ShieldedTxData = (*MintTxData)(nil)
+	_ ShieldedTxData = (*SendTxData)(nil)
+	_ ShieldedTxData = (*DepositTxData)(nil)
+	_ ShieldedTxData = (*RedeemTxData)(nil)
+)
+
+// ShieldedTxData is the payload of a shielded transaction. Following EIP-2718,
+// a shielded transaction is encoded as its type byte followed by the RLP list of
+// its TxData and its payload, while public transactions keep the legacy Ethereum
+// encoding. Each type has its own payload, holding only the zk fields it uses.
+type ShieldedTxData interface {
+	// TxType returns the type of the transactions carrying the payload.
+	TxType() uint8
+
+	zkFields() zkFields
+	// signingData returns the fields of the payload covered by the signatures of
+	// the transaction.
+	signingData() interface{}
+}
+
+// zkFields points to the zk fields of a payload. The fields its type doesn't
+// have are nil.
+type zkFields struct {
+	value                      *uint64
+	sn, sns, cmtOld, cmt, cmts *ethcmn.Hash
+	rtcmt                      *ethcmn.Hash
+	proof, aux                 *[]byte
+	x, y                       **big.Int
+}
+
+// NewShieldedTxData returns an empty payload of the given shielded transaction
+// type.
+func NewShieldedTxData(txType uint8) (ShieldedTxData, error) {
+	switch txType {
+	case MintTx:
+		return &MintTxData{}, nil
+	case SendTx:
+		return &SendTxData{}, nil
+	case DepositTx:
+		return &DepositTxData{}, nil
+	case RedeemTx:
+		return &RedeemTxData{}, nil
+	default:
+		return nil, sdkerrors.Wrapf(ErrMalformedShieldedTx, "unsupported transaction type %d", txType)
+	}
+}
+
+// ----------------------------------------------------------------------------
+// Mint
+
+// MintTxData is the payload of a MintTx, moving Value from the public balance
+// of the sender into a new note.
+type MintTxData struct {
+	Value  uint64
+	SN     ethcmn.Hash
+	CMTOld ethcmn.Hash
+	CMT    ethcmn.Hash
+	Proof  []byte
+}
+
+type mintTxDataJSON struct {
+	Value  hexutil.Uint64 `json:"value"`
+	SN     ethcmn.Hash    `json:"sn"`
+	CMTOld ethcmn.Hash    `json:"cmtOld"`
+	CMT    ethcmn.Hash    `json:"cmt"`
+	Proof  hexutil.Bytes  `json:"proof"`
+}
+
+// TxType implements ShieldedTxData.
+func (d *MintTxData) TxType() uint8 { return MintTx }
+
+func (d *MintTxData) signingData() interface{} { return d }
+
+func (d *MintTxData) zkFields() zkFields {
+	return zkFields{value: &d.Value, sn: &d.SN, cmtOld: &d.CMTOld, cmt: &d.CMT, proof: &d.Proof}
+}
+
+// MarshalAmino encodes the payload with RLP.
+func (d MintTxData) MarshalAmino() ([]byte, error) {
+	return rlp.EncodeToBytes(&d)
+}
+
+// UnmarshalAmino decodes an RLP encoded payload.
+func (d *MintTxData) UnmarshalAmino(bz []byte) error {
+	return rlp.DecodeBytes(bz, d)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d MintTxData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(mintTxDataJSON{
+		Value:  hexutil.Uint64(d.Value),
+		SN:     d.SN,
+		CMTOld: d.CMTOld,
+		CMT:    d.CMT,
+		Proof:  d.Proof,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *MintTxData) UnmarshalJSON(bz []byte) error {
+	var dec mintTxDataJSON
+	if err := json.Unmarshal(bz, &dec); err != nil {
+		return err
+	}
+
+	*d = MintTxData{
+		Value:  uint64(dec.Value),
+		SN:     dec.SN,
+		CMTOld: dec.CMTOld,
+		CMT:    dec.CMT,
+		Proof:  dec.Proof,
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// Send
+
+// SendTxData is the payload of a SendTx, splitting a note of the sender into a
+// send commitment CMTS for the receiver and a new note for the change. The value
+// of the send is encrypted in AUX for the one-time public key (X, Y).
+//
+// The send circuit does not commit to a fee, so a SendTx cannot be relayed: its
+// gas is paid in plaintext by the sender of the spent note, like any other
+// transaction.
+type SendTxData struct {
+	SN     ethcmn.Hash
+	CMTOld ethcmn.Hash
+	CMT    ethcmn.Hash
+	CMTS   ethcmn.Hash
+	Proof  []byte
+	AUX    []byte
+	X      *big.Int
+	Y      *big.Int
+}
+
+type sendTxDataJSON struct {
+	SN     ethcmn.Hash   `json:"sn"`
+	CMTOld ethcmn.Hash   `json:"cmtOld"`
+	CMT    ethcmn.Hash   `json:"cmt"`
+	CMTS   ethcmn.Hash   `json:"cmts"`
+	Proof  hexutil.Bytes `json:"proof"`
+	AUX    hexutil.Bytes `json:"aux"`
+	X      *hexutil.Big  `json:"x"`
+	Y      *hexutil.Big  `json:"y"`
+}
+
+// TxType implements ShieldedTxData.
+func (d *SendTxData) TxType() uint8 { return SendTx }
+
+func (d *SendTxData) signingData() interface{} { return d }
+
+func (d *SendTxData) zkFields() zkFields {
+	return zkFields{
+		sn: &d.SN, cmtOld: &d.CMTOld, cmt: &d.CMT, cmts: &d.CMTS,
+		proof: &d.Proof, aux: &d.AUX, x: &d.X, y: &d.Y,
+	}
+}
+
+// MarshalAmino encodes the payload with RLP.
+func (d SendTxData) MarshalAmino() ([]byte, error) {
+	return rlp.EncodeToBytes(&d)
+}
+
+// UnmarshalAmino decodes an RLP encoded payload.
+func (d *SendTxData) UnmarshalAmino(bz []byte) error {
+	return rlp.DecodeBytes(bz, d)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d SendTxData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sendTxDataJSON{
+		SN:     d.SN,
+		CMTOld: d.CMTOld,
+		CMT:    d.CMT,
+		CMTS:   d.CMTS,
+		Proof:  d.Proof,
+		AUX:    d.AUX,
+		X:      (*hexutil.Big)(d.X),
+		Y:      (*hexutil.Big)(d.Y),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *SendTxData) UnmarshalJSON(bz []byte) error {
+	var dec sendTxDataJSON
+	if err := json.Unmarshal(bz, &dec); err != nil {
+		return err
+	}
+
+	*d = SendTxData{
+		SN:     dec.SN,
+		CMTOld: dec.CMTOld,
+		CMT:    dec.CMT,
+		CMTS:   dec.CMTS,
+		Proof:  dec.Proof,
+		AUX:    dec.AUX,
+		X:      (*big.Int)(dec.X),
+		Y:      (*big.Int)(dec.Y),
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// Deposit
+
+// DepositTxData is the payload of a DepositTx, claiming the send commitment of
+// serial number SNS into a new note of the receiver. The send commitment is
+// proven against the commitment root RTcmt, and the claim is signed (V, R, S)
+// with the one-time private key of the public key (X, Y) it was sent to.
+type DepositTxData struct {
+	SN     ethcmn.Hash
+	SNS    ethcmn.Hash
+	CMTOld ethcmn.Hash
+	CMT    ethcmn.Hash
+	RTcmt  ethcmn.Hash
+	Proof  []byte
+	X      *big.Int
+	Y      *big.Int
+
+	// one-time key signature values
+	V *big.Int
+	R *big.Int
+	S *big.Int
+}
+
+type depositTxDataJSON struct {
+	SN     ethcmn.Hash   `json:"sn"`
+	SNS    ethcmn.Hash   `json:"sns"`
+	CMTOld ethcmn.Hash   `json:"cmtOld"`
+	CMT    ethcmn.Hash   `json:"cmt"`
+	RTcmt  ethcmn.Hash   `json:"rtcmt"`
+	Proof  hexutil.Bytes `json:"proof"`
+	X      *hexutil.Big  `json:"x"`
+	Y      *hexutil.Big  `json:"y"`
+	V      *hexutil.Big  `json:"v"`
+	R      *hexutil.Big  `json:"r"`
+	S      *hexutil.Big  `json:"s"`
+}
+
+// TxType implements ShieldedTxData.
+func (d *DepositTxData) TxType() uint8 { return DepositTx }
+
+// signingData leaves out the one-time key signature, which signs the same bytes
+// as the sender.
+func (d *DepositTxData) signingData() interface{} {
+	unsigned := *d
+	unsigned.V, unsigned.R, unsigned.S = nil, nil, nil
+	return &unsigned
+}
+
+func (d *DepositTxData) zkFields() zkFields {
+	return zkFields{
+		sn: &d.SN, sns: &d.SNS, cmtOld: &d.CMTOld, cmt: &d.CMT, rtcmt: &d.RTcmt,
+		proof: &d.Proof, x: &d.X, y: &d.Y,
+	}
+}
+
+// MarshalAmino encodes the payload with RLP.
+func (d DepositTxData) MarshalAmino() ([]byte, error) {
+	return rlp.EncodeToBytes(&d)
+}
+
+// UnmarshalAmino decodes an RLP encoded payload.
+func (d *DepositTxData) UnmarshalAmino(bz []byte) error {
+	return rlp.DecodeBytes(bz, d)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d DepositTxData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(depositTxDataJSON{
+		SN:     d.SN,
+		SNS:    d.SNS,
+		CMTOld: d.CMTOld,
+		CMT:    d.CMT,
+		RTcmt:  d.RTcmt,
+		Proof:  d.Proof,
+		X:      (*hexutil.Big)(d.X),
+		Y:      (*hexutil.Big)(d.Y),
+		V:      (*hexutil.Big)(d.V),
+		R:      (*hexutil.Big)(d.R),
+		S:      (*hexutil.Big)(d.S),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *DepositTxData) UnmarshalJSON(bz []byte) error {
+	var dec depositTxDataJSON
+	if err := json.Unmarshal(bz, &dec); err != nil {
+		return err
+	}
+
+	*d = DepositTxData{
+		SN:     dec.SN,
+		SNS:    dec.SNS,
+		CMTOld: dec.CMTOld,
+		CMT:    dec.CMT,
+		RTcmt:  dec.RTcmt,
+		Proof:  dec.Proof,
+		X:      (*big.Int)(dec.X),
+		Y:      (*big.Int)(dec.Y),
+		V:      (*big.Int)(dec.V),
+		R:      (*big.Int)(dec.R),
+		S:      (*big.Int)(dec.S),
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// Redeem
+
+// RedeemTxData is the payload of a RedeemTx, moving Value from a note of the
+// sender back to its public balance.
+type RedeemTxData struct {
+	Value  uint64
+	SN     ethcmn.Hash
+	CMTOld ethcmn.Hash
+	CMT    ethcmn.Hash
+	Proof  []byte
+}
+
+type redeemTxDataJSON struct {
+	Value  hexutil.Uint64 `json:"value"`
+	SN     ethcmn.Hash    `json:"sn"`
+	CMTOld ethcmn.Hash    `json:"cmtOld"`
+	CMT    ethcmn.Hash    `json:"cmt"`
+	Proof  hexutil.Bytes  `json:"proof"`
+}
+
+// TxType implements ShieldedTxData.
+func (d *RedeemTxData) TxType() uint8 { return RedeemTx }
+
+func (d *RedeemTxData) signingData() interface{} { return d }
+
+func (d *RedeemTxData) zkFields() zkFields {
+	return zkFields{value: &d.Value, sn: &d.SN, cmtOld: &d.CMTOld, cmt: &d.CMT, proof: &d.Proof}
+}
+
+// MarshalAmino encodes the payload with RLP.
+func (d RedeemTxData) MarshalAmino() ([]byte, error) {
+	return rlp.EncodeToBytes(&d)
+}
+
+// UnmarshalAmino decodes an RLP encoded payload.
+func (d *RedeemTxData) UnmarshalAmino(bz []byte) error {
+	return rlp.DecodeBytes(bz, d)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d RedeemTxData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redeemTxDataJSON{
+		Value:  hexutil.Uint64(d.Value),
+		SN:     d.SN,
+		CMTOld: d.CMTOld,
+		CMT:    d.CMT,
+		Proof:  d.Proof,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *RedeemTxData) UnmarshalJSON(bz []byte) error {
+	var dec redeemTxDataJSON
+	if err := json.Unmarshal(bz, &dec); err != nil {
+		return err
+	}
+
+	*d = RedeemTxData{
+		Value:  uint64(dec.Value),
+		SN:     dec.SN,
+		CMTOld: dec.CMTOld,
+		CMT:    dec.CMT,
+		Proof:  dec.Proof,
+	}
+	return nil
+}
+
+// shieldedString returns a short description of the zk fields of a payload.
+func shieldedString(data ShieldedTxData) string {
+	f := data.zkFields()
+	return fmt.Sprintf("type=%d sn=%s cmtOld=%s cmt=%s proof=0x%x", data.TxType(), f.sn.Hex(), f.cmtOld.Hex(), f.cmt.Hex(), *f.proof)
+}
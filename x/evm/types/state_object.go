@@ -3,7 +3,6 @@ package types
 import (
 	"bytes"
 	"fmt"
-	"github.com/cosmos/ethermint/zktx"
 	"io"
 	"math/big"
 
@@ -45,7 +44,6 @@ type StateObject interface {
 
 	SetNonce(nonce uint64)
 	Nonce() uint64
-	CMTBalance() ethcmn.Hash
 }
 
 // stateObject represents an Ethereum account which is being modified.
@@ -94,15 +92,6 @@ func newStateObject(db *CommitStateDB, accProto authexported.Account) *stateObje
 		ethermintAccount.CodeHash = emptyCodeHash
 	}
 
-	if ethermintAccount.CMT ==(ethcmn.Hash{}){
-		// For large-scale test, we suppose that SK = CRH(addr), there is impossible in pratical.
-		SK := zktx.ZKTxAddress.Hash()
-		r := ethcmn.Hash{}
-		sn := zktx.ComputePRF(SK.Bytes(), r.Bytes()) // sn = PRF(sk, r)
-		cmt := zktx.GenCMT(0, sn.Bytes(), r.Bytes())
-		ethermintAccount.CMT = *cmt
-	}
-
 	return &stateObject{
 		stateDB:                 db,
 		account:                 ethermintAccount,
@@ -464,16 +453,3 @@ type stateEntry struct {
 	stateObject *stateObject
 }
 
-
-////add for blockmaze
-func (so *stateObject) CMTBalance() ethcmn.Hash {
-	return so.account.CMT
-}
-
-func (self *stateObject) SetCMT(cmt *ethcmn.Hash) {
-	self.setCMT(*cmt)
-}
-
-func (self *stateObject) setCMT(cmt ethcmn.Hash) {
-	self.account.CMT = cmt
-}
\ No newline at end of file
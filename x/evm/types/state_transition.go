@@ -24,6 +24,10 @@ type StateTransition struct {
 	Amount       *big.Int
 	Payload      []byte
 
+	// zk-SNARK fields
+	TxCode  uint8
+	ZKValue uint64
+
 	ChainID  *big.Int
 	Csdb     *CommitStateDB
 	TxHash   *common.Hash
@@ -127,6 +131,9 @@ func (st StateTransition) TransitionDb(ctx sdk.Context, config ChainConfig) (*Ex
 		senderRef       = vm.AccountRef(st.Sender)
 	)
 
+	// the changes of the EVM are reverted if the shielded transfer fails
+	snapshot := csdb.Snapshot()
+
 	// Get nonce of account outside of the EVM
 	currentNonce := csdb.GetNonce(st.Sender)
 	// Set nonce of sender account before evm state transition for usage in generating Create address
@@ -155,6 +162,14 @@ func (st StateTransition) TransitionDb(ctx sdk.Context, config ChainConfig) (*Ex
 	// Resets nonce to value pre state transition
 	csdb.SetNonce(st.Sender, currentNonce)
 
+	// Move the minted and redeemed value between the sender's public balance
+	// and the shielded pool
+	if err := st.transferShielded(csdb); err != nil {
+		csdb.RevertToSnapshot(snapshot)
+		ctx.GasMeter().ConsumeGas(gasConsumed, "evm execution consumption")
+		return nil, err
+	}
+
 	// Generate bloom filter to be saved in tx receipt data
 	bloomInt := big.NewInt(0)
 
@@ -221,13 +236,39 @@ func (st StateTransition) TransitionDb(ctx sdk.Context, config ChainConfig) (*Ex
 	// Consume gas from evm execution
 	// Out of gas check does not need to be done here since it is done within the EVM execution
 	ctx.WithGasMeter(currentGasMeter).GasMeter().ConsumeGas(gasConsumed, "EVM execution consumption")
-	//txCode := tx.TxCode()
-	//cmt := msg.CMT()
-	//if txCode == types.MintTx {
-	//	st.state.SubBalance(msg.From(), big.NewInt(int64(msg.ZKValue())))
-	//}
-	//if txCode == types.RedeemTx {
-	//	st.state.AddBalance(msg.From(), big.NewInt(int64(msg.ZKValue())))
-	//}
 	return executionResult, nil
 }
+
+// transferShielded debits the value of a mint transaction from the sender's
+// balance and credits the value of a redeem transaction to it. The shielded
+// pool is updated through the journal along with the balances, so that it is
+// left untouched by simulations and reverted transactions.
+func (st StateTransition) transferShielded(csdb *CommitStateDB) error {
+	value := new(big.Int).SetUint64(st.ZKValue)
+
+	switch st.TxCode {
+	case MintTx:
+		balance := csdb.GetBalance(st.Sender)
+		if balance.Cmp(value) < 0 {
+			return sdkerrors.Wrapf(
+				sdkerrors.ErrInsufficientFunds,
+				"insufficient balance to mint %s, got %s", value, balance,
+			)
+		}
+		csdb.SubBalance(st.Sender, value)
+	case RedeemTx:
+		supply := csdb.GetShieldedSupply()
+		if supply.Cmp(value) < 0 {
+			return sdkerrors.Wrapf(
+				sdkerrors.ErrInsufficientFunds,
+				"insufficient shielded supply to redeem %s, got %s", value, supply,
+			)
+		}
+		csdb.AddBalance(st.Sender, value)
+		value.Neg(value)
+	default:
+		return nil
+	}
+
+	return csdb.AddShieldedSupply(value)
+}
@@ -145,3 +145,68 @@ func (suite *StateDBTestSuite) TestTransitionDb() {
 		}
 	}
 }
+
+func (suite *StateDBTestSuite) TestTransitionDbShielded() {
+	suite.stateDB.SetNonce(suite.address, 123)
+
+	addr := sdk.AccAddress(suite.address.Bytes())
+	balance := ethermint.NewPhotonCoin(sdk.NewInt(5000))
+	acc := suite.app.AccountKeeper.GetAccount(suite.ctx, addr)
+	_ = acc.SetCoins(sdk.NewCoins(balance))
+	suite.app.AccountKeeper.SetAccount(suite.ctx, acc)
+
+	testCases := []struct {
+		name       string
+		txCode     uint8
+		value      uint64
+		expPass    bool
+		expBalance int64
+		expSupply  int64
+	}{
+		{"mint", types.MintTx, 1000, true, 4000, 1000},
+		{"mint more than balance", types.MintTx, 10000, false, 4000, 1000},
+		{"redeem", types.RedeemTx, 400, true, 4400, 600},
+		{"redeem more than shielded supply", types.RedeemTx, 1000, false, 4400, 600},
+		{"send", types.SendTx, 100, true, 4400, 600},
+	}
+
+	for _, tc := range testCases {
+		// the value transferred by the EVM is reverted along with a failed
+		// shielded transfer
+		recipient := ethcmn.BytesToAddress([]byte(tc.name))
+		amount := int64(0)
+		if !tc.expPass {
+			amount = 100
+		}
+		nonce := suite.stateDB.GetNonce(suite.address)
+
+		st := types.StateTransition{
+			AccountNonce: 123,
+			Price:        big.NewInt(10),
+			GasLimit:     11,
+			Recipient:    &recipient,
+			Amount:       big.NewInt(amount),
+			TxCode:       tc.txCode,
+			ZKValue:      tc.value,
+			ChainID:      big.NewInt(1),
+			Csdb:         suite.stateDB,
+			TxHash:       &ethcmn.Hash{},
+			Sender:       suite.address,
+		}
+
+		_, err := st.TransitionDb(suite.ctx, types.DefaultChainConfig())
+		if tc.expPass {
+			suite.Require().NoError(err, tc.name)
+		} else {
+			suite.Require().Error(err, tc.name)
+			suite.Require().Zero(suite.stateDB.GetBalance(recipient).Sign(), tc.name)
+			suite.Require().Equal(nonce, suite.stateDB.GetNonce(suite.address), tc.name)
+		}
+
+		suite.Require().Equal(big.NewInt(tc.expBalance), suite.app.EvmKeeper.GetBalance(suite.ctx, suite.address), tc.name)
+		suite.Require().Equal(big.NewInt(tc.expSupply), suite.app.EvmKeeper.GetShieldedSupply(suite.ctx), tc.name)
+
+		pool := suite.app.AccountKeeper.GetAccount(suite.ctx, types.ShieldedPoolAddress)
+		suite.Require().Equal(sdk.NewInt(tc.expSupply), pool.GetCoins().AmountOf(ethermint.AttoPhoton), tc.name)
+	}
+}
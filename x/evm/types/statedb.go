@@ -3,12 +3,14 @@ package types
 import (
 	"fmt"
 	"math/big"
+	"reflect"
 	"sort"
 	"sync"
 
 	"github.com/cosmos/cosmos-sdk/store/prefix"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/supply"
 
 	emint "github.com/cosmos/ethermint/types"
 
@@ -23,6 +25,10 @@ var (
 	_ ethvm.StateDB = (*CommitStateDB)(nil)
 
 	zeroBalance = sdk.ZeroInt().BigInt()
+
+	// ShieldedPoolAddress is the address of the module account holding the
+	// EVM denomination minted into the shielded pool.
+	ShieldedPoolAddress = supply.NewModuleAddress(ModuleName)
 )
 
 type revision struct {
@@ -55,6 +61,10 @@ type CommitStateDB struct {
 	// The refund counter, also used by state transitioning.
 	refund uint64
 
+	// Amount moved into the shielded pool since the last commit, written along
+	// with the state objects.
+	shieldedSupplyDelta *big.Int
+
 	thash, bhash ethcmn.Hash
 	txIndex      int
 	logSize      uint
@@ -118,6 +128,85 @@ func (csdb *CommitStateDB) SetParams(params Params) {
 	csdb.paramSpace.SetParamSet(csdb.ctx, &params)
 }
 
+// SetMissingParams sets the evm parameters missing from the param space to their
+// default value, leaving the other ones unchanged.
+func (csdb *CommitStateDB) SetMissingParams() {
+	params := DefaultParams()
+	for _, pair := range params.ParamSetPairs() {
+		if csdb.paramSpace.Has(csdb.ctx, pair.Key) {
+			continue
+		}
+		csdb.paramSpace.Set(csdb.ctx, pair.Key, reflect.Indirect(reflect.ValueOf(pair.Value)).Interface())
+	}
+}
+
+// SetShieldedSupply sets the total amount of the EVM denomination held in the
+// shielded pool.
+func (csdb *CommitStateDB) SetShieldedSupply(supply *big.Int) {
+	store := csdb.ctx.KVStore(csdb.storeKey)
+	store.Set(KeyPrefixShieldedSupply, supply.Bytes())
+}
+
+// AddShieldedSupply moves the given amount of the EVM denomination into the
+// shielded pool, or out of it if the amount is negative. Like balance changes,
+// the move is journaled and only written to the shielded pool account and the
+// shielded supply when the state is finalised or committed.
+func (csdb *CommitStateDB) AddShieldedSupply(amount *big.Int) error {
+	total := new(big.Int).Add(csdb.GetShieldedSupply(), amount)
+	if total.Sign() == -1 {
+		return fmt.Errorf("shielded supply cannot be negative, got %s", total)
+	}
+
+	prev := csdb.shieldedSupplyDelta
+	csdb.journal.append(shieldedSupplyChange{prev: prev})
+	if prev == nil {
+		prev = new(big.Int)
+	}
+	csdb.shieldedSupplyDelta = new(big.Int).Add(prev, amount)
+	return nil
+}
+
+// commitShieldedSupply moves the pending shielded supply change between the
+// shielded pool account and the store.
+func (csdb *CommitStateDB) commitShieldedSupply() error {
+	amount := csdb.shieldedSupplyDelta
+	if amount == nil {
+		return nil
+	}
+	csdb.shieldedSupplyDelta = nil
+
+	store := csdb.ctx.KVStore(csdb.storeKey)
+	total := new(big.Int).Add(new(big.Int).SetBytes(store.Get(KeyPrefixShieldedSupply)), amount)
+	if total.Sign() == -1 {
+		return fmt.Errorf("shielded supply cannot be negative, got %s", total)
+	}
+
+	acc := csdb.accountKeeper.GetAccount(csdb.ctx, ShieldedPoolAddress)
+	if acc == nil {
+		acc = csdb.accountKeeper.NewAccount(csdb.ctx, supply.NewEmptyModuleAccount(ModuleName))
+	}
+
+	evmDenom := csdb.GetParams().EvmDenom
+	coins := sdk.NewCoins(sdk.NewCoin(evmDenom, sdk.NewIntFromBigInt(new(big.Int).Abs(amount))))
+	balance := acc.GetCoins()
+	if amount.Sign() == -1 {
+		var hasNeg bool
+		if balance, hasNeg = balance.SafeSub(coins); hasNeg {
+			return fmt.Errorf("insufficient shielded pool balance %s to redeem %s", acc.GetCoins(), coins)
+		}
+	} else {
+		balance = balance.Add(coins...)
+	}
+
+	if err := acc.SetCoins(balance); err != nil {
+		return err
+	}
+
+	csdb.accountKeeper.SetAccount(csdb.ctx, acc)
+	csdb.SetShieldedSupply(total)
+	return nil
+}
+
 // SetBalance sets the balance of an account.
 func (csdb *CommitStateDB) SetBalance(addr ethcmn.Address, amount *big.Int) {
 	so := csdb.GetOrNewStateObject(addr)
@@ -253,6 +342,17 @@ func (csdb *CommitStateDB) GetParams() (params Params) {
 	return params
 }
 
+// GetShieldedSupply returns the total amount of the EVM denomination held in
+// the shielded pool, including the amounts moved since the last commit.
+func (csdb *CommitStateDB) GetShieldedSupply() *big.Int {
+	store := csdb.ctx.KVStore(csdb.storeKey)
+	supply := new(big.Int).SetBytes(store.Get(KeyPrefixShieldedSupply))
+	if csdb.shieldedSupplyDelta != nil {
+		supply.Add(supply, csdb.shieldedSupplyDelta)
+	}
+	return supply
+}
+
 // GetBalance retrieves the balance from the given address or 0 if object not
 // found.
 func (csdb *CommitStateDB) GetBalance(addr ethcmn.Address) *big.Int {
@@ -441,6 +541,10 @@ func (csdb *CommitStateDB) Commit(deleteEmptyObjects bool) (ethcmn.Hash, error)
 		delete(csdb.stateObjectsDirty, stateEntry.address)
 	}
 
+	if err := csdb.commitShieldedSupply(); err != nil {
+		return ethcmn.Hash{}, err
+	}
+
 	// NOTE: Ethereum returns the trie merkle root here, but as commitment
 	// actually happens in the BaseApp at EndBlocker, we do not know the root at
 	// this time.
@@ -481,6 +585,10 @@ func (csdb *CommitStateDB) Finalise(deleteEmptyObjects bool) error {
 		csdb.stateObjectsDirty[dirty.address] = struct{}{}
 	}
 
+	if err := csdb.commitShieldedSupply(); err != nil {
+		return err
+	}
+
 	// invalidate journal because reverting across transactions is not allowed
 	csdb.clearJournalAndRefund()
 	return nil
@@ -632,6 +740,7 @@ func (csdb *CommitStateDB) Reset(_ ethcmn.Hash) error {
 	csdb.logSize = 0
 	csdb.preimages = []preimageEntry{}
 	csdb.hashToPreimageIndex = make(map[ethcmn.Hash]int)
+	csdb.shieldedSupplyDelta = nil
 
 	csdb.clearJournalAndRefund()
 	return nil
@@ -716,6 +825,7 @@ func (csdb *CommitStateDB) Copy() *CommitStateDB {
 		stateObjectsDirty:    make(map[ethcmn.Address]struct{}),
 		refund:               csdb.refund,
 		logSize:              csdb.logSize,
+		shieldedSupplyDelta:  csdb.shieldedSupplyDelta,
 		preimages:            make([]preimageEntry, len(csdb.preimages)),
 		hashToPreimageIndex:  make(map[ethcmn.Hash]int, len(csdb.hashToPreimageIndex)),
 		journal:              newJournal(),
@@ -628,6 +628,35 @@ func (suite *StateDBTestSuite) TestCommitStateDB_Snapshot() {
 	}, "invalid revision should panic")
 }
 
+func (suite *StateDBTestSuite) TestCommitStateDB_ShieldedSupply() {
+	poolCoins := func() sdk.Int {
+		pool := suite.app.AccountKeeper.GetAccount(suite.ctx, types.ShieldedPoolAddress)
+		if pool == nil {
+			return sdk.ZeroInt()
+		}
+		return pool.GetCoins().AmountOf(ethermint.AttoPhoton)
+	}
+
+	// a reverted move leaves the shielded pool untouched
+	id := suite.stateDB.Snapshot()
+	suite.Require().NoError(suite.stateDB.AddShieldedSupply(big.NewInt(100)))
+	suite.Require().Equal(big.NewInt(100), suite.stateDB.GetShieldedSupply())
+	suite.stateDB.RevertToSnapshot(id)
+	suite.Require().NoError(suite.stateDB.Finalise(false))
+	suite.Require().Equal(big.NewInt(0), suite.stateDB.GetShieldedSupply())
+	suite.Require().True(poolCoins().IsZero())
+
+	// the move is only written to the store once finalised
+	suite.Require().NoError(suite.stateDB.AddShieldedSupply(big.NewInt(100)))
+	suite.Require().True(poolCoins().IsZero())
+	suite.Require().NoError(suite.stateDB.Finalise(false))
+	suite.Require().Equal(big.NewInt(100), suite.app.EvmKeeper.GetShieldedSupply(suite.ctx))
+	suite.Require().Equal(sdk.NewInt(100), poolCoins())
+
+	// the supply can't go negative
+	suite.Require().Error(suite.stateDB.AddShieldedSupply(big.NewInt(-101)))
+}
+
 func (suite *StateDBTestSuite) TestCommitStateDB_ForEachStorage() {
 	var storage types.Storage
 
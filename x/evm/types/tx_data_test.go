@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 func TestMarshalAndUnmarshalData(t *testing.T) {
@@ -54,3 +55,73 @@ func TestMsgEthereumTxAmino(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, msg, msg2)
 }
+
+func newTestShieldedTxs() []MsgEthereumTx {
+	addr := GenerateEthAddress()
+	sn, cmtOld, cmt := ethcmn.BytesToHash([]byte("sn")), ethcmn.BytesToHash([]byte("cmt old")), ethcmn.BytesToHash([]byte("cmt"))
+	payloads := []ShieldedTxData{
+		&MintTxData{Value: 10, SN: sn, CMTOld: cmtOld, CMT: cmt, Proof: []byte("proof")},
+		&SendTxData{
+			SN: sn, CMTOld: cmtOld, CMT: cmt, CMTS: ethcmn.BytesToHash([]byte("cmts")),
+			Proof: []byte("proof"), AUX: []byte("aux"), X: big.NewInt(1), Y: big.NewInt(2),
+		},
+		&DepositTxData{
+			SN: sn, SNS: ethcmn.BytesToHash([]byte("sns")), CMTOld: cmtOld, CMT: cmt, RTcmt: ethcmn.BytesToHash([]byte("root")),
+			Proof: []byte("proof"), X: big.NewInt(1), Y: big.NewInt(2), V: big.NewInt(3), R: big.NewInt(4), S: big.NewInt(5),
+		},
+		&RedeemTxData{Value: 10, SN: sn, CMTOld: cmtOld, CMT: cmt, Proof: []byte("proof")},
+	}
+
+	msgs := make([]MsgEthereumTx, len(payloads))
+	for i, payload := range payloads {
+		msgs[i] = NewMsgEthereumTx(5, &addr, big.NewInt(0), 100000, big.NewInt(3), []byte("test"))
+		msgs[i].Data.V = big.NewInt(1)
+		msgs[i].Data.R = big.NewInt(2)
+		msgs[i].Data.S = big.NewInt(3)
+		msgs[i].Shielded = payload
+	}
+	return msgs
+}
+
+func TestShieldedTxEncoding(t *testing.T) {
+	for _, msg := range newTestShieldedTxs() {
+		msg := msg
+
+		// typed envelope
+		bz, err := msg.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, msg.Code(), bz[0])
+
+		var decoded MsgEthereumTx
+		require.NoError(t, decoded.UnmarshalBinary(bz))
+		require.Equal(t, msg.Data, decoded.Data)
+		require.Equal(t, msg.Shielded, decoded.Shielded)
+
+		// RLP string within a stream
+		raw, err := rlp.EncodeToBytes(&msg)
+		require.NoError(t, err)
+		decoded = MsgEthereumTx{}
+		require.NoError(t, rlp.DecodeBytes(raw, &decoded))
+		require.Equal(t, msg.Shielded, decoded.Shielded)
+
+		// amino
+		raw, err = ModuleCdc.MarshalBinaryBare(msg)
+		require.NoError(t, err)
+		decoded = MsgEthereumTx{}
+		require.NoError(t, ModuleCdc.UnmarshalBinaryBare(raw, &decoded))
+		require.Equal(t, msg.Data, decoded.Data)
+		require.Equal(t, msg.Shielded, decoded.Shielded)
+
+		// JSON
+		raw, err = ModuleCdc.MarshalJSON(msg)
+		require.NoError(t, err)
+		decoded = MsgEthereumTx{}
+		require.NoError(t, ModuleCdc.UnmarshalJSON(raw, &decoded))
+		require.Equal(t, msg.Shielded, decoded.Shielded)
+	}
+
+	// unsupported types are rejected
+	var msg MsgEthereumTx
+	require.Error(t, msg.UnmarshalBinary([]byte{UpdateTx, 0xc0}))
+	require.Error(t, msg.UnmarshalBinary(nil))
+}
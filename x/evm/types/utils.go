@@ -53,6 +53,17 @@ func rlpHash(x interface{}) (hash ethcmn.Hash) {
 	return hash
 }
 
+// prefixedRlpHash returns the hash of the prefix byte followed by the RLP
+// encoding of x.
+func prefixedRlpHash(prefix byte, x interface{}) (hash ethcmn.Hash) {
+	hasher := sha3.NewLegacyKeccak256()
+	_, _ = hasher.Write([]byte{prefix})
+	_ = rlp.Encode(hasher, x)
+	_ = hasher.Sum(hash[:0])
+
+	return hash
+}
+
 // ResultData represents the data returned in an sdk.Result
 type ResultData struct {
 	ContractAddress ethcmn.Address  `json:"contract_address"`
@@ -0,0 +1,183 @@
+package groth16
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// Proofs and verifying keys are expected as affine points with every coordinate
+// written as a fixed width, big endian, 64 character hex word. This is an
+// assumed export format that hasn't been checked against the libsnark circuits.
+// G2 coordinates are written imaginary part first, following the EIP-197
+// encoding. Words may be concatenated or separated by whitespace, commas or
+// brackets, and may carry a "0x" prefix.
+//
+//	proof:         A (G1) | B (G2) | C (G1)
+//	verifying key: alpha (G1) | beta (G2) | gamma (G2) | delta (G2) | IC_0 ... IC_n (G1)
+const (
+	wordSize = 32
+	g1Words  = 2
+	g2Words  = 4
+
+	proofWords = 2*g1Words + g2Words
+)
+
+// ErrEncoding is returned when a proof or verifying key cannot be decoded.
+var ErrEncoding = errors.New("groth16: invalid encoding")
+
+// ParseProof decodes a proof in the hex encoding emitted by the circuits.
+func ParseProof(data []byte) (*Proof, error) {
+	words, err := decodeWords(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) != proofWords {
+		return nil, fmt.Errorf("%w: proof has %d words, expected %d", ErrEncoding, len(words), proofWords)
+	}
+
+	// the provers return an all zero proof when proof generation fails
+	if isZero(words[0:2]) || isZero(words[2:6]) || isZero(words[6:8]) {
+		return nil, fmt.Errorf("%w: proof contains the point at infinity", ErrEncoding)
+	}
+
+	proof := new(Proof)
+	if proof.A, err = decodeG1(words[0:2]); err != nil {
+		return nil, err
+	}
+	if proof.B, err = decodeG2(words[2:6]); err != nil {
+		return nil, err
+	}
+	if proof.C, err = decodeG1(words[6:8]); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// ParseVerifyingKey decodes a verifying key in the hex encoding emitted by the
+// circuits.
+func ParseVerifyingKey(data []byte) (*VerifyingKey, error) {
+	words, err := decodeWords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	fixed := g1Words + 3*g2Words
+	if len(words) < fixed+g1Words || (len(words)-fixed)%g1Words != 0 {
+		return nil, fmt.Errorf("%w: verifying key has %d words", ErrEncoding, len(words))
+	}
+
+	vk := new(VerifyingKey)
+	if vk.Alpha, err = decodeG1(words[0:2]); err != nil {
+		return nil, err
+	}
+	if vk.Beta, err = decodeG2(words[2:6]); err != nil {
+		return nil, err
+	}
+	if vk.Gamma, err = decodeG2(words[6:10]); err != nil {
+		return nil, err
+	}
+	if vk.Delta, err = decodeG2(words[10:14]); err != nil {
+		return nil, err
+	}
+
+	for i := fixed; i < len(words); i += g1Words {
+		ic, err := decodeG1(words[i : i+g1Words])
+		if err != nil {
+			return nil, err
+		}
+		vk.IC = append(vk.IC, ic)
+	}
+	return vk, nil
+}
+
+// EncodeProof returns the hex encoding of the proof.
+func EncodeProof(proof *Proof) []byte {
+	var sb strings.Builder
+	sb.WriteString(hex.EncodeToString(proof.A.Marshal()))
+	sb.WriteString(hex.EncodeToString(proof.B.Marshal()))
+	sb.WriteString(hex.EncodeToString(proof.C.Marshal()))
+	return []byte(sb.String())
+}
+
+// EncodeVerifyingKey returns the hex encoding of the verifying key, one point
+// per line.
+func EncodeVerifyingKey(vk *VerifyingKey) []byte {
+	lines := []string{
+		hex.EncodeToString(vk.Alpha.Marshal()),
+		hex.EncodeToString(vk.Beta.Marshal()),
+		hex.EncodeToString(vk.Gamma.Marshal()),
+		hex.EncodeToString(vk.Delta.Marshal()),
+	}
+	for _, ic := range vk.IC {
+		lines = append(lines, hex.EncodeToString(ic.Marshal()))
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// decodeWords splits the input into 32 byte words.
+func decodeWords(data []byte) ([][]byte, error) {
+	fields := strings.FieldsFunc(string(data), func(r rune) bool {
+		switch r {
+		case ' ', '\t', '\n', '\r', ',', '[', ']', '"':
+			return true
+		}
+		return false
+	})
+
+	var words [][]byte
+	for _, field := range fields {
+		field = strings.TrimPrefix(strings.TrimPrefix(field, "0x"), "0X")
+		if len(field) == 0 || len(field)%(2*wordSize) != 0 {
+			return nil, fmt.Errorf("%w: hex field of length %d", ErrEncoding, len(field))
+		}
+
+		bz, err := hex.DecodeString(field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrEncoding, err)
+		}
+
+		for i := 0; i < len(bz); i += wordSize {
+			words = append(words, bz[i:i+wordSize])
+		}
+	}
+	return words, nil
+}
+
+func decodeG1(words [][]byte) (*bn256.G1, error) {
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(join(words)); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrEncoding, err)
+	}
+	return p, nil
+}
+
+func decodeG2(words [][]byte) (*bn256.G2, error) {
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(join(words)); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrEncoding, err)
+	}
+	return p, nil
+}
+
+func isZero(words [][]byte) bool {
+	for _, w := range words {
+		for _, b := range w {
+			if b != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func join(words [][]byte) []byte {
+	bz := make([]byte, 0, len(words)*wordSize)
+	for _, w := range words {
+		bz = append(bz, w...)
+	}
+	return bz
+}
@@ -0,0 +1,82 @@
+// Package groth16 implements a pure Go verifier for Groth16 zk-SNARK proofs over
+// the BN254 (alt_bn128) curve. It does not depend on libsnark and can therefore
+// be used by nodes that are built without the native proving libraries.
+package groth16
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+var (
+	// ErrInvalidProof is returned when the pairing check of a proof fails.
+	ErrInvalidProof = errors.New("groth16: invalid proof")
+
+	// ErrInputLength is returned when the number of public inputs doesn't match
+	// the verifying key.
+	ErrInputLength = errors.New("groth16: public input length mismatch")
+
+	// ErrInputRange is returned when a public input is not a reduced element of the
+	// scalar field.
+	ErrInputRange = errors.New("groth16: public input out of range")
+)
+
+// Order is the order of the BN254 scalar field Fr, in which the public inputs
+// of the circuits live.
+var Order = bn256.Order
+
+// VerifyingKey is a Groth16 verifying key.
+type VerifyingKey struct {
+	Alpha *bn256.G1
+	Beta  *bn256.G2
+	Gamma *bn256.G2
+	Delta *bn256.G2
+	// IC holds the linear combination bases for the public inputs. IC[0] is the
+	// constant term, so len(IC) equals the number of public inputs plus one.
+	IC []*bn256.G1
+}
+
+// NumInputs returns the number of public inputs expected by the verifying key.
+func (vk *VerifyingKey) NumInputs() int {
+	return len(vk.IC) - 1
+}
+
+// Proof is a Groth16 proof.
+type Proof struct {
+	A *bn256.G1
+	B *bn256.G2
+	C *bn256.G1
+}
+
+// Verify checks the proof against the verifying key and the given public inputs.
+// It returns ErrInvalidProof if the pairing equation
+//
+//	e(A, B) = e(alpha, beta) * e(IC(inputs), gamma) * e(C, delta)
+//
+// does not hold, where IC(inputs) = IC[0] + sum(inputs[i] * IC[i+1]).
+func Verify(vk *VerifyingKey, proof *Proof, inputs []*big.Int) error {
+	if len(inputs) != vk.NumInputs() {
+		return fmt.Errorf("%w: got %d, expected %d", ErrInputLength, len(inputs), vk.NumInputs())
+	}
+
+	acc := new(bn256.G1).Set(vk.IC[0])
+	for i, input := range inputs {
+		if input.Sign() < 0 || input.Cmp(Order) >= 0 {
+			return fmt.Errorf("%w: input %d", ErrInputRange, i)
+		}
+		acc.Add(acc, new(bn256.G1).ScalarMult(vk.IC[i+1], input))
+	}
+
+	negA := new(bn256.G1).Neg(proof.A)
+	ok := bn256.PairingCheck(
+		[]*bn256.G1{negA, vk.Alpha, acc, proof.C},
+		[]*bn256.G2{proof.B, vk.Beta, vk.Gamma, vk.Delta},
+	)
+	if !ok {
+		return ErrInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package groth16
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// setup returns a verifying key with random trapdoors for a circuit with n public
+// inputs, together with a function that forges a valid proof for any input
+// vector using those trapdoors.
+func setup(t *testing.T, n int) (*VerifyingKey, func(inputs []*big.Int) *Proof) {
+	alpha, beta, gamma, delta := randScalar(t), randScalar(t), randScalar(t), randScalar(t)
+
+	ic := make([]*big.Int, n+1)
+	vk := &VerifyingKey{
+		Alpha: new(bn256.G1).ScalarBaseMult(alpha),
+		Beta:  new(bn256.G2).ScalarBaseMult(beta),
+		Gamma: new(bn256.G2).ScalarBaseMult(gamma),
+		Delta: new(bn256.G2).ScalarBaseMult(delta),
+	}
+	for i := range ic {
+		ic[i] = randScalar(t)
+		vk.IC = append(vk.IC, new(bn256.G1).ScalarBaseMult(ic[i]))
+	}
+
+	prove := func(inputs []*big.Int) *Proof {
+		a, b := randScalar(t), randScalar(t)
+
+		// c = (a*b - alpha*beta - gamma*(ic_0 + sum(ic_i * x_i))) / delta
+		acc := new(big.Int).Set(ic[0])
+		for i, x := range inputs {
+			acc.Add(acc, new(big.Int).Mul(ic[i+1], x))
+		}
+		c := new(big.Int).Mul(a, b)
+		c.Sub(c, new(big.Int).Mul(alpha, beta))
+		c.Sub(c, new(big.Int).Mul(gamma, acc))
+		c.Mul(c, new(big.Int).ModInverse(delta, Order))
+		c.Mod(c, Order)
+
+		return &Proof{
+			A: new(bn256.G1).ScalarBaseMult(a),
+			B: new(bn256.G2).ScalarBaseMult(b),
+			C: new(bn256.G1).ScalarBaseMult(c),
+		}
+	}
+	return vk, prove
+}
+
+func randScalar(t *testing.T) *big.Int {
+	k, err := rand.Int(rand.Reader, Order)
+	require.NoError(t, err)
+	return k
+}
+
+func TestVerify(t *testing.T) {
+	vk, prove := setup(t, 3)
+	inputs := []*big.Int{big.NewInt(1), big.NewInt(42), new(big.Int).Sub(Order, big.NewInt(1))}
+	proof := prove(inputs)
+
+	require.NoError(t, Verify(vk, proof, inputs))
+
+	wrong := []*big.Int{big.NewInt(1), big.NewInt(43), inputs[2]}
+	require.Equal(t, ErrInvalidProof, Verify(vk, proof, wrong))
+	require.Error(t, Verify(vk, proof, inputs[:2]))
+	require.Error(t, Verify(vk, proof, []*big.Int{inputs[0], inputs[1], Order}))
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	vk, prove := setup(t, 2)
+	inputs := []*big.Int{big.NewInt(7), big.NewInt(9)}
+	proof := prove(inputs)
+
+	decodedVK, err := ParseVerifyingKey(EncodeVerifyingKey(vk))
+	require.NoError(t, err)
+	require.Equal(t, 2, decodedVK.NumInputs())
+
+	encoded := EncodeProof(proof)
+	require.Len(t, encoded, proofWords*2*wordSize)
+
+	decodedProof, err := ParseProof(append([]byte("0x"), encoded...))
+	require.NoError(t, err)
+	require.NoError(t, Verify(decodedVK, decodedProof, inputs))
+}
+
+func TestParseProofInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"not hex", "zz"},
+		{"short word", "0x1234"},
+		{"all zero", strings.Repeat("0", proofWords*2*wordSize)},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseProof([]byte(tc.data))
+		require.Error(t, err, tc.name)
+	}
+}
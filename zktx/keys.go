@@ -0,0 +1,113 @@
+package zktx
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/cosmos/ethermint/crypto/hd"
+)
+
+// ShieldedKeys holds the shielded keys of an account.
+//
+// It deviates from the requested key hierarchy, which has a spending key
+// separate from the nullifier key: the mint, send, deposit and redeem circuits
+// constrain the SN of a spent note to sn = PRF(sk, r) with sk the SK of the
+// witness, so the key deriving the SNs has to be the one authorizing spends.
+// A separate spending key needs circuits that derive the SNs from another
+// witness than the one proving ownership. Until then the keys are:
+//   - the nullifier key is the secret of the PRF deriving the SNs of the notes
+//     of the account, sn = PRF(nk, r), and is given to the circuits as SK
+//   - the incoming viewing key derives the one-time keys that the notes are sent
+//     to and decrypts their AUX
+type ShieldedKeys struct {
+	NullifierKey       common.Hash
+	IncomingViewingKey *ecdsa.PrivateKey
+}
+
+// NewShieldedKeys derives the shielded keys of an account from its eth_secp256k1
+// private key, through a common secret.
+func NewShieldedKeys(privKey *ecdsa.PrivateKey) *ShieldedKeys {
+	ask := deriveShieldedKey("zktx spending key", crypto.FromECDSA(privKey))
+	return &ShieldedKeys{
+		NullifierKey:       deriveShieldedKey("zktx nullifier key", ask.Bytes()),
+		IncomingViewingKey: deriveViewingKey("zktx incoming viewing key", ask.Bytes()),
+	}
+}
+
+// NewShieldedKeysFromMnemonic derives the shielded keys of the account at the
+// given BIP-44 path of the mnemonic.
+func NewShieldedKeysFromMnemonic(mnemonic, bip39Passphrase, hdPath string) (*ShieldedKeys, error) {
+	bz, err := hd.DeriveSecp256k1(mnemonic, bip39Passphrase, hdPath)
+	if err != nil {
+		return nil, err
+	}
+
+	privKey, err := crypto.ToECDSA(bz)
+	if err != nil {
+		return nil, err
+	}
+	return NewShieldedKeys(privKey), nil
+}
+
+// SK returns the key given to the circuits when spending the note. The initial
+// note is spent with InitialSK, every other note with the nullifier key.
+func (keys *ShieldedKeys) SK(note *Sequence) (*common.Hash, error) {
+	initialSN, _, err := InitialNote()
+	if err != nil {
+		return nil, err
+	}
+	if note.SN != nil && *note.SN == initialSN {
+		sk := InitialSK
+		return &sk, nil
+	}
+
+	nk := keys.NullifierKey
+	return &nk, nil
+}
+
+// IncomingViewingPubKey returns the public key that senders encrypt the notes
+// of the account to, RLP encoded as expected by the pubKey of a send transaction.
+func (keys *ShieldedKeys) IncomingViewingPubKey() ([]byte, error) {
+	pub := keys.IncomingViewingKey.PublicKey
+	return rlp.EncodeToBytes([]interface{}{pub.X, pub.Y})
+}
+
+// ReceiveNote decrypts the AUX of a send transaction with the incoming viewing
+// key. R is the public key of the send transaction and sna the SN it spends,
+// which the AUX repeats. It returns false if the note isn't sent to the keys.
+func (keys *ShieldedKeys) ReceiveNote(R *ecdsa.PublicKey, aux []byte, sna common.Hash) (ReceivedNote, bool) {
+	randomKey := GenerateKeyForRandomB(R, keys.IncomingViewingKey)
+	value, rs, auxSNA, err := DecAUX(randomKey, aux)
+	if err != nil || *auxSNA != sna {
+		return ReceivedNote{}, false
+	}
+
+	return ReceivedNote{Value: value, RS: *rs, SNA: sna, Key: crypto.PubkeyToAddress(randomKey.PublicKey)}, true
+}
+
+func deriveShieldedKey(domain string, data ...[]byte) common.Hash {
+	h := sha256.New()
+	h.Write([]byte(domain))
+	for _, bz := range data {
+		h.Write(bz)
+	}
+	return common.BytesToHash(h.Sum(nil))
+}
+
+// deriveViewingKey hashes the secret to a valid secp256k1 scalar, retrying with
+// a counter in the unlikely case the hash is out of range.
+func deriveViewingKey(domain string, secret []byte) *ecdsa.PrivateKey {
+	var counter [4]byte
+	for i := uint32(0); ; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		key := deriveShieldedKey(domain, secret, counter[:])
+		if priv, err := crypto.ToECDSA(key.Bytes()); err == nil {
+			return priv
+		}
+	}
+}
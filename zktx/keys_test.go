@@ -0,0 +1,104 @@
+package zktx
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/cosmos/ethermint/crypto/hd"
+)
+
+func TestShieldedKeys(t *testing.T) {
+	SetInitialNote(common.BytesToHash([]byte("sn")), common.BytesToHash([]byte("cmt")))
+
+	priv1, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	priv2, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	keys := NewShieldedKeys(priv1)
+	require.Equal(t, keys, NewShieldedKeys(priv1))
+	require.NotEqual(t, crypto.FromECDSA(priv1), keys.NullifierKey.Bytes())
+	require.NotEqual(t, crypto.FromECDSA(priv1), crypto.FromECDSA(keys.IncomingViewingKey))
+
+	// every account has its own keys
+	other := NewShieldedKeys(priv2)
+	require.NotEqual(t, keys.NullifierKey, other.NullifierKey)
+	require.NotEqual(t, keys.IncomingViewingKey.D, other.IncomingViewingKey.D)
+
+	// the initial note is spent with the public initial key, the others with
+	// the nullifier key
+	initial, err := InitializeSN()
+	require.NoError(t, err)
+	sk, err := keys.SK(initial)
+	require.NoError(t, err)
+	require.Equal(t, InitialSK, *sk)
+	sn := common.BytesToHash([]byte("note"))
+	sk, err = keys.SK(&Sequence{SN: &sn})
+	require.NoError(t, err)
+	require.Equal(t, keys.NullifierKey, *sk)
+
+	// the public key is encoded as the pubKey of a send transaction
+	bz, err := keys.IncomingViewingPubKey()
+	require.NoError(t, err)
+	var pub struct {
+		X *big.Int
+		Y *big.Int
+	}
+	require.NoError(t, rlp.DecodeBytes(bz, &pub))
+	require.Equal(t, keys.IncomingViewingKey.PublicKey.X, pub.X)
+	require.Equal(t, keys.IncomingViewingKey.PublicKey.Y, pub.Y)
+}
+
+func TestShieldedKeysFromMnemonic(t *testing.T) {
+	entropy, err := bip39.NewEntropy(256)
+	require.NoError(t, err)
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	require.NoError(t, err)
+	hdPath := "m/44'/60'/0'/0/0"
+
+	keys, err := NewShieldedKeysFromMnemonic(mnemonic, "", hdPath)
+	require.NoError(t, err)
+
+	bz, err := hd.DeriveSecp256k1(mnemonic, "", hdPath)
+	require.NoError(t, err)
+	priv, err := crypto.ToECDSA(bz)
+	require.NoError(t, err)
+	require.Equal(t, NewShieldedKeys(priv), keys)
+
+	_, err = NewShieldedKeysFromMnemonic("invalid mnemonic", "", hdPath)
+	require.Error(t, err)
+	_, err = NewShieldedKeysFromMnemonic(mnemonic, "", "/wrong/hdPath")
+	require.Error(t, err)
+}
+
+func TestReceiveNote(t *testing.T) {
+	priv1, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	priv2, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	receiver, other := NewShieldedKeys(priv1), NewShieldedKeys(priv2)
+
+	// the sender encrypts the AUX to a one-time key of the receiver
+	R := GenR()
+	randomReceiverPK := NewRandomPubKey(R.D, receiver.IncomingViewingKey.PublicKey)
+	rs, sna := common.BytesToHash([]byte("rs")), common.BytesToHash([]byte("sna"))
+	aux, err := ComputeAUX(randomReceiverPK, 5, &rs, &sna)
+	require.NoError(t, err)
+
+	note, ok := receiver.ReceiveNote(&R.PublicKey, aux, sna)
+	require.True(t, ok)
+	randomKey := GenerateKeyForRandomB(&R.PublicKey, receiver.IncomingViewingKey)
+	require.Equal(t, ReceivedNote{Value: 5, RS: rs, SNA: sna, Key: crypto.PubkeyToAddress(randomKey.PublicKey)}, note)
+
+	_, ok = other.ReceiveNote(&R.PublicKey, aux, sna)
+	require.False(t, ok)
+	_, ok = receiver.ReceiveNote(&R.PublicKey, aux, rs)
+	require.False(t, ok)
+}
@@ -0,0 +1,85 @@
+//go:build !libsnark
+// +build !libsnark
+
+package zktx
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LibsnarkEnabled reports whether the binary was built with the libsnark tag.
+const LibsnarkEnabled = false
+
+var _ Verifier = disabledVerifier{}
+
+// disabledVerifier rejects every proof, it is the default verifier of builds
+// without libsnark.
+type disabledVerifier struct{}
+
+// DefaultVerifier returns a verifier rejecting every proof since the binary was
+// built without libsnark. The experimental Groth16Verifier is the only verifier
+// of such builds.
+func DefaultVerifier() Verifier {
+	return disabledVerifier{}
+}
+
+func (disabledVerifier) VerifyMintProof(*common.Hash, *common.Hash, *common.Hash, uint64, []byte) error {
+	return ErrLibsnarkDisabled
+}
+
+func (disabledVerifier) VerifySendProof(*common.Hash, *common.Hash, []byte, *common.Hash, *common.Hash) error {
+	return ErrLibsnarkDisabled
+}
+
+func (disabledVerifier) VerifyDepositProof(*ecdsa.PublicKey, common.Hash, *common.Hash, *common.Hash, *common.Hash, *common.Hash, []byte) error {
+	return ErrLibsnarkDisabled
+}
+
+func (disabledVerifier) VerifyRedeemProof(*common.Hash, *common.Hash, *common.Hash, uint64, []byte) error {
+	return ErrLibsnarkDisabled
+}
+
+func GenCMT(value uint64, sn []byte, r []byte) *common.Hash {
+	panic(ErrLibsnarkDisabled)
+}
+
+func GenCMTS(values uint64, pk_recv *ecdsa.PublicKey, rs []byte, sna []byte) *common.Hash {
+	panic(ErrLibsnarkDisabled)
+}
+
+func ComputePRF(sk []byte, r []byte) *common.Hash {
+	panic(ErrLibsnarkDisabled)
+}
+
+func ComputeCRH(pk_recv common.Address, r []byte) *common.Hash {
+	panic(ErrLibsnarkDisabled)
+}
+
+// GenRT returns the root of the commitment tree holding the given commitments,
+// computed like the genRoot gadget of the deposit circuit. It panics if there
+// are more than MerkleTreeLeaves commitments.
+func GenRT(CMTSForMerkle []*common.Hash) common.Hash {
+	leaves := make([]common.Hash, len(CMTSForMerkle))
+	for i, cmts := range CMTSForMerkle {
+		leaves[i] = *cmts
+	}
+	return MerkleRoot(leaves)
+}
+
+func GenMintProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) []byte {
+	panic(ErrLibsnarkDisabled)
+}
+
+func GenSendProof(CMTA *common.Hash, ValueA uint64, RA *common.Hash, ValueS uint64, pk_recv *ecdsa.PublicKey, RS *common.Hash, SNA *common.Hash, CMTS *common.Hash, ValueAnew uint64, SNAnew *common.Hash, RAnew *common.Hash, CMTAnew *common.Hash, SK *common.Hash, pk_sender common.Address) []byte {
+	panic(ErrLibsnarkDisabled)
+}
+
+func GenDepositProof(CMTS *common.Hash, ValueS uint64, SNS *common.Hash, RS *common.Hash, SNA *common.Hash, ValueB uint64, RB *common.Hash, SNBnew *common.Hash, RBnew *common.Hash, pk_recv *ecdsa.PublicKey, RTcmt []byte, CMTB *common.Hash, SNB *common.Hash, CMTBnew *common.Hash, CMTSForMerkle []*common.Hash, SK *common.Hash) []byte {
+	panic(ErrLibsnarkDisabled)
+}
+
+func GenRedeemProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) []byte {
+	panic(ErrLibsnarkDisabled)
+}
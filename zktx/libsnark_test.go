@@ -0,0 +1,26 @@
+//go:build libsnark
+// +build libsnark
+
+package zktx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestGenRT checks that the commitment tree rebuilt by the deposit circuit is
+// the one maintained on chain with MerkleHash.
+func TestGenRT(t *testing.T) {
+	var leaves []common.Hash
+	var cmts []*common.Hash
+	for i := 0; i <= MerkleTreeLeaves; i++ {
+		require.Equal(t, MerkleRoot(leaves), GenRT(cmts), "%d leaves", i)
+
+		leaf := common.BytesToHash([]byte{byte(i + 1)})
+		leaves = append(leaves, leaf)
+		cmts = append(cmts, &leaf)
+	}
+}
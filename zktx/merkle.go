@@ -0,0 +1,145 @@
+package zktx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MerkleTreeDepth is the depth of the commitment trees holding the send
+// commitments (CMTS). It is the depth of the tree rebuilt by the genRoot gadget
+// of the deposit circuit, which hashes at most MerkleTreeLeaves commitments.
+const MerkleTreeDepth = 5
+
+// MerkleTreeLeaves is the number of leaves of a commitment tree, i.e. the
+// largest number of send commitments a deposit proof can be built against.
+// The send commitments fill a new tree once the previous one is full.
+const MerkleTreeLeaves = 1 << MerkleTreeDepth
+
+// MerkleHash returns the parent of two nodes of the commitment tree. Like
+// libsnark's sha256_two_to_one_hash_gadget used by the deposit circuit, it is
+// the SHA-256 compression function applied to the initial hash values and the
+// 64 byte block left || right, without any padding.
+func MerkleHash(left, right common.Hash) common.Hash {
+	var block [64]byte
+	copy(block[:32], left[:])
+	copy(block[32:], right[:])
+
+	h := sha256IV
+	sha256Compress(&h, &block)
+
+	var out common.Hash
+	for i, v := range h {
+		binary.BigEndian.PutUint32(out[4*i:], v)
+	}
+	return out
+}
+
+// emptyNodes[i] is the root of an empty subtree of height i, the leaves of
+// the empty tree being zero.
+var emptyNodes = func() (nodes [MerkleTreeDepth + 1]common.Hash) {
+	for i := 1; i <= MerkleTreeDepth; i++ {
+		nodes[i] = MerkleHash(nodes[i-1], nodes[i-1])
+	}
+	return nodes
+}()
+
+// EmptyMerkleNode returns the root of an empty subtree of the given height.
+func EmptyMerkleNode(height int) common.Hash {
+	return emptyNodes[height]
+}
+
+// MerkleRoot computes the root of the commitment tree holding the given leaves.
+// It panics if there are more than MerkleTreeLeaves leaves.
+func MerkleRoot(leaves []common.Hash) common.Hash {
+	if len(leaves) > MerkleTreeLeaves {
+		panic(fmt.Sprintf("commitment tree holds at most %d leaves, got %d", MerkleTreeLeaves, len(leaves)))
+	}
+
+	level := make([]common.Hash, len(leaves))
+	copy(level, leaves)
+
+	for height := 0; height < MerkleTreeDepth; height++ {
+		if len(level)%2 == 1 {
+			level = append(level, emptyNodes[height])
+		}
+		parents := make([]common.Hash, len(level)/2)
+		for i := range parents {
+			parents[i] = MerkleHash(level[2*i], level[2*i+1])
+		}
+		level = parents
+	}
+
+	if len(level) == 0 {
+		return emptyNodes[MerkleTreeDepth]
+	}
+	return level[0]
+}
+
+// MerklePathRoot returns the root of the commitment tree computed from a leaf,
+// its index and the authentication path of its siblings, leaf level first.
+func MerklePathRoot(leaf common.Hash, index uint64, path []common.Hash) common.Hash {
+	node := leaf
+	for _, sibling := range path {
+		if index&1 == 0 {
+			node = MerkleHash(node, sibling)
+		} else {
+			node = MerkleHash(sibling, node)
+		}
+		index >>= 1
+	}
+	return node
+}
+
+var sha256IV = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+	0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var sha256K = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+// sha256Compress applies the SHA-256 compression function to the state h and
+// a single message block.
+func sha256Compress(h *[8]uint32, block *[64]byte) {
+	var w [64]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(block[4*i:])
+	}
+	for i := 16; i < 64; i++ {
+		s0 := bits.RotateLeft32(w[i-15], -7) ^ bits.RotateLeft32(w[i-15], -18) ^ (w[i-15] >> 3)
+		s1 := bits.RotateLeft32(w[i-2], -17) ^ bits.RotateLeft32(w[i-2], -19) ^ (w[i-2] >> 10)
+		w[i] = w[i-16] + s0 + w[i-7] + s1
+	}
+
+	a, b, c, d, e, f, g, hh := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+	for i := 0; i < 64; i++ {
+		s1 := bits.RotateLeft32(e, -6) ^ bits.RotateLeft32(e, -11) ^ bits.RotateLeft32(e, -25)
+		ch := (e & f) ^ (^e & g)
+		t1 := hh + s1 + ch + sha256K[i] + w[i]
+		s0 := bits.RotateLeft32(a, -2) ^ bits.RotateLeft32(a, -13) ^ bits.RotateLeft32(a, -22)
+		maj := (a & b) ^ (a & c) ^ (b & c)
+		t2 := s0 + maj
+
+		hh, g, f, e, d, c, b, a = g, f, e, d+t1, c, b, a, t1+t2
+	}
+
+	h[0] += a
+	h[1] += b
+	h[2] += c
+	h[3] += d
+	h[4] += e
+	h[5] += f
+	h[6] += g
+	h[7] += hh
+}
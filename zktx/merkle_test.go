@@ -0,0 +1,73 @@
+package zktx
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSHA256Compress(t *testing.T) {
+	for _, msg := range []string{"", "abc"} {
+		// a single padded block
+		var block [64]byte
+		copy(block[:], msg)
+		block[len(msg)] = 0x80
+		block[63] = byte(8 * len(msg))
+
+		h := sha256IV
+		sha256Compress(&h, &block)
+
+		var out common.Hash
+		for i, v := range h {
+			out[4*i], out[4*i+1], out[4*i+2], out[4*i+3] = byte(v>>24), byte(v>>16), byte(v>>8), byte(v)
+		}
+		require.Equal(t, common.Hash(sha256.Sum256([]byte(msg))), out, msg)
+	}
+}
+
+func TestMerkleRoot(t *testing.T) {
+	require.Equal(t, EmptyMerkleNode(MerkleTreeDepth), MerkleRoot(nil))
+
+	a := common.BytesToHash([]byte("a"))
+	b := common.BytesToHash([]byte("b"))
+	c := common.BytesToHash([]byte("c"))
+
+	expected := MerkleHash(
+		MerkleHash(MerkleHash(a, b), MerkleHash(c, EmptyMerkleNode(0))),
+		EmptyMerkleNode(2),
+	)
+	for height := 3; height < MerkleTreeDepth; height++ {
+		expected = MerkleHash(expected, EmptyMerkleNode(height))
+	}
+	require.Equal(t, expected, MerkleRoot([]common.Hash{a, b, c}))
+
+	path := []common.Hash{c, MerkleHash(a, b)}
+	for height := 2; height < MerkleTreeDepth; height++ {
+		path = append(path, EmptyMerkleNode(height))
+	}
+	require.Equal(t, expected, MerklePathRoot(EmptyMerkleNode(0), 3, path))
+}
+
+func TestMerkleRootFullTree(t *testing.T) {
+	leaves := make([]common.Hash, MerkleTreeLeaves)
+	for i := range leaves {
+		leaves[i] = common.BytesToHash([]byte{byte(i + 1)})
+	}
+
+	level := leaves
+	for len(level) > 1 {
+		var parents []common.Hash
+		for i := 0; i < len(level); i += 2 {
+			parents = append(parents, MerkleHash(level[i], level[i+1]))
+		}
+		level = parents
+	}
+	require.Equal(t, level[0], MerkleRoot(leaves))
+
+	require.Panics(t, func() {
+		MerkleRoot(append(leaves, common.Hash{}))
+	})
+}
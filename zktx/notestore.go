@@ -0,0 +1,394 @@
+package zktx
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	dbm "github.com/tendermint/tm-db"
+)
+
+// pendingTimeout is the number of blocks after which a shielded transaction of
+// an account that hasn't been processed is considered failed.
+const pendingTimeout = 20
+
+var (
+	// ErrInsufficientShieldedBalance is returned when the notes of an account
+	// don't hold enough value for a transaction.
+	ErrInsufficientShieldedBalance = errors.New("not enough shielded balance")
+
+	// ErrNotesFragmented is returned when the notes of an account hold enough
+	// value for a transaction but none of them holds it alone.
+	ErrNotesFragmented = errors.New("no single note holds enough shielded balance, merge the notes first")
+)
+
+// AccountNotes holds the shielded state of an account.
+type AccountNotes struct {
+	// Unspent holds the notes of the account that are confirmed on chain. The
+	// zero valued initial note, which every account holds, isn't part of it.
+	Unspent []*Sequence
+
+	// Pending holds the shielded transactions of the account that have been
+	// broadcast but not processed yet.
+	Pending []*PendingTx
+
+	// SNS is the note sent by the last send transaction and RandomReceiverPK the
+	// one-time public key of its receiver.
+	SNS              *Sequence
+	RandomReceiverPK *ecdsa.PublicKey
+
+	// Received holds the notes sent to the account and ScanHeight the last block
+	// scanned for them.
+	Received   []*ReceivedNote
+	ScanHeight int64
+
+	// Created holds the notes created by shielded transactions of the account
+	// that haven't been replayed from the chain yet.
+	Created []*Sequence
+}
+
+// PendingTx is a broadcast shielded transaction of an account, spending the
+// note Spent and creating the note Created. Height is the latest block height
+// when it was broadcast.
+type PendingTx struct {
+	Spent   *Sequence
+	Created *Sequence
+	Height  int64
+}
+
+// NewAccountNotes returns the notes of an account that hasn't made any shielded
+// transaction yet.
+func NewAccountNotes() *AccountNotes {
+	return &AccountNotes{}
+}
+
+// AddReceived records a note sent to the account and returns it, or returns the
+// already recorded note with the same send commitment.
+func (notes *AccountNotes) AddReceived(note ReceivedNote) *ReceivedNote {
+	for _, received := range notes.Received {
+		if received.CMTS == note.CMTS {
+			return received
+		}
+	}
+
+	notes.Received = append(notes.Received, &note)
+	return &note
+}
+
+// Track records a note created by a shielded transaction of the account. It is
+// called before the transaction is broadcast, so that Replay can recover the
+// note if the transaction is processed but the wallet isn't updated.
+func (notes *AccountNotes) Track(note *Sequence) {
+	notes.Created = append(notes.Created, note)
+}
+
+// AddPending records a broadcast shielded transaction. The note it spends can't
+// be selected until the transaction is processed or has failed.
+func (notes *AccountNotes) AddPending(spent, created *Sequence, height int64) {
+	notes.Pending = append(notes.Pending, &PendingTx{Spent: spent, Created: created, Height: height})
+}
+
+// Available returns the unspent notes holding a value that no pending
+// transaction spends.
+func (notes *AccountNotes) Available() []*Sequence {
+	spending := make(map[common.Hash]bool)
+	for _, tx := range notes.Pending {
+		spending[*tx.Spent.CMT] = true
+	}
+
+	var available []*Sequence
+	for _, note := range notes.Unspent {
+		if note.Value > 0 && !spending[*note.CMT] {
+			available = append(available, note)
+		}
+	}
+	return available
+}
+
+// Balance returns the value of the unspent notes, and the value the account
+// will hold once its pending transactions are processed.
+func (notes *AccountNotes) Balance() (confirmed, pending uint64) {
+	for _, note := range notes.Unspent {
+		confirmed += note.Value
+	}
+	for _, note := range notes.Available() {
+		pending += note.Value
+	}
+	for _, tx := range notes.Pending {
+		pending += tx.Created.Value
+	}
+	return confirmed, pending
+}
+
+// SelectNote returns the note spent by a send or redeem transaction of the
+// given value: the available note with the smallest value that covers it, so
+// that the larger notes are kept for larger transactions.
+func (notes *AccountNotes) SelectNote(value uint64) (*Sequence, error) {
+	var (
+		selected *Sequence
+		total    uint64
+	)
+	for _, note := range notes.Available() {
+		total += note.Value
+		if note.Value >= value && (selected == nil || note.Value < selected.Value) {
+			selected = note
+		}
+	}
+
+	switch {
+	case selected != nil:
+		return selected, nil
+	case total >= value:
+		return nil, ErrNotesFragmented
+	default:
+		return nil, ErrInsufficientShieldedBalance
+	}
+}
+
+// SelectMerge returns the two available notes with the smallest values, the
+// first one to be sent to the account itself and deposited into the second
+// one. It returns false if fewer than two notes are available.
+func (notes *AccountNotes) SelectMerge() (from, into *Sequence, ok bool) {
+	available := notes.Available()
+	if len(available) < 2 {
+		return nil, nil, false
+	}
+
+	sort.SliceStable(available, func(i, j int) bool {
+		return available[i].Value < available[j].Value
+	})
+	return available[0], available[1], true
+}
+
+// Sync updates the notes with the commitments of the unspent notes of the
+// account on chain at the given height. Pending transactions whose created note
+// is on chain are confirmed. Those whose spent note has been spent by another
+// transaction, or that have been pending for more than pendingTimeout blocks,
+// are dropped. Unspent notes that are no longer on chain have been spent.
+func (notes *AccountNotes) Sync(onChain []common.Hash, height int64) error {
+	_, initialCMT, err := InitialNote()
+	if err != nil {
+		return err
+	}
+	chain := map[common.Hash]bool{initialCMT: true}
+	for _, cmt := range onChain {
+		chain[cmt] = true
+	}
+
+	var pending []*PendingTx
+	for _, tx := range notes.Pending {
+		switch {
+		case chain[*tx.Created.CMT]:
+			notes.Unspent = append(notes.Unspent, tx.Created)
+			notes.untrack(tx.Created)
+		case !chain[*tx.Spent.CMT]:
+			// the spent note has been spent by another transaction
+		case height > tx.Height+pendingTimeout:
+			// the transaction failed or has been dropped
+		default:
+			pending = append(pending, tx)
+		}
+	}
+
+	var unspent []*Sequence
+	for _, note := range notes.Unspent {
+		if chain[*note.CMT] && *note.CMT != initialCMT {
+			unspent = append(unspent, note)
+		}
+	}
+	notes.Unspent, notes.Pending = unspent, pending
+	return nil
+}
+
+func (notes *AccountNotes) untrack(note *Sequence) {
+	for i, created := range notes.Created {
+		if *created.CMT == *note.CMT {
+			notes.Created = append(notes.Created[:i:i], notes.Created[i+1:]...)
+			return
+		}
+	}
+}
+
+// ShieldedTx is a shielded transaction sent by an account, as processed by the
+// chain: it spends the note with commitment Spent and creates the note with
+// commitment CMT.
+type ShieldedTx struct {
+	Spent common.Hash
+	CMT   common.Hash
+}
+
+// Replay rebuilds the unspent notes of the account from all its shielded
+// transactions, in chain order. Every transaction spends either the initial
+// note or a note created by an earlier transaction, and the notes still unspent
+// at the end must be known to the wallet. Created notes that no transaction
+// reached are kept, and the pending transactions are dropped.
+func (notes *AccountNotes) Replay(txs []ShieldedTx) error {
+	known := make(map[common.Hash]*Sequence)
+	for _, note := range notes.Unspent {
+		known[*note.CMT] = note
+	}
+	for _, tx := range notes.Pending {
+		known[*tx.Created.CMT] = tx.Created
+	}
+	for _, note := range notes.Created {
+		known[*note.CMT] = note
+	}
+
+	var held []common.Hash
+	spend := func(cmt common.Hash) bool {
+		for i := range held {
+			if held[i] == cmt {
+				held = append(held[:i:i], held[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+
+	_, initialCMT, err := InitialNote()
+	if err != nil {
+		return err
+	}
+	reached := make(map[common.Hash]bool)
+	for _, tx := range txs {
+		if tx.Spent != initialCMT && !spend(tx.Spent) {
+			return fmt.Errorf("shielded transaction spends note %s that isn't unspent", tx.Spent.Hex())
+		}
+		held = append(held, tx.CMT)
+		reached[tx.CMT] = true
+	}
+
+	var unspent, created []*Sequence
+	for _, cmt := range held {
+		note, ok := known[cmt]
+		if !ok {
+			return fmt.Errorf("note of commitment %s unknown to the wallet", cmt.Hex())
+		}
+		unspent = append(unspent, note)
+	}
+	for _, note := range notes.Created {
+		if !reached[*note.CMT] {
+			created = append(created, note)
+		}
+	}
+
+	notes.Unspent, notes.Pending, notes.Created = unspent, nil, created
+	return nil
+}
+
+// NoteStore holds the shielded notes of many accounts, keyed by their Ethereum
+// address. Each account has its own lock, so that requests for different
+// accounts run concurrently while requests for the same account are serialized.
+//
+// The notes of an account are saved to the wallet database as a single record,
+// encrypted with the wallet key of the account, so that every update is atomic.
+type NoteStore struct {
+	mtx      sync.Mutex
+	accounts map[common.Address]*lockedNotes
+	db       dbm.DB
+}
+
+type lockedNotes struct {
+	mtx   sync.Mutex
+	notes *AccountNotes
+	key   []byte
+}
+
+// NewNoteStore creates an empty NoteStore saving the notes to the given wallet
+// database. A nil database keeps the notes in memory only.
+func NewNoteStore(db dbm.DB) *NoteStore {
+	return &NoteStore{
+		accounts: make(map[common.Address]*lockedNotes),
+		db:       db,
+	}
+}
+
+func (ns *NoteStore) entry(address common.Address) *lockedNotes {
+	ns.mtx.Lock()
+	defer ns.mtx.Unlock()
+
+	entry, ok := ns.accounts[address]
+	if !ok {
+		entry = new(lockedNotes)
+		ns.accounts[address] = entry
+	}
+	return entry
+}
+
+// Open sets the wallet key of the given account, derived with DeriveWalletKey,
+// and loads its saved notes from the wallet database.
+func (ns *NoteStore) Open(address common.Address, key []byte) error {
+	entry := ns.entry(address)
+	entry.mtx.Lock()
+	defer entry.mtx.Unlock()
+
+	entry.key = key
+	if ns.db == nil {
+		return nil
+	}
+
+	bz, err := ns.db.Get(walletKey(address))
+	if err != nil || bz == nil {
+		return err
+	}
+
+	version, plaintext, err := openNotes(key, address, bz)
+	if err != nil {
+		return err
+	}
+	decode := decodeNotes
+	if version == walletVersionV1 {
+		decode = decodeNotesV1
+	}
+	notes, err := decode(plaintext)
+	if err != nil {
+		return err
+	}
+
+	entry.notes = notes
+	return nil
+}
+
+// Lock locks the notes of the given account and returns them, creating them on
+// first use. The caller may update them until it calls Unlock.
+func (ns *NoteStore) Lock(address common.Address) *AccountNotes {
+	entry := ns.entry(address)
+	entry.mtx.Lock()
+	if entry.notes == nil {
+		entry.notes = NewAccountNotes()
+	}
+	return entry.notes
+}
+
+// Unlock unlocks the notes of the given account.
+func (ns *NoteStore) Unlock(address common.Address) {
+	ns.entry(address).mtx.Unlock()
+}
+
+// Save persists the notes of the given account to the wallet database. The
+// caller must hold the lock of the account.
+func (ns *NoteStore) Save(address common.Address, notes *AccountNotes) error {
+	if ns.db == nil {
+		return nil
+	}
+
+	entry := ns.entry(address)
+	if entry.key == nil {
+		return ErrWalletLocked
+	}
+
+	plaintext, err := encodeNotes(notes)
+	if err != nil {
+		return err
+	}
+	bz, err := sealNotes(entry.key, address, plaintext)
+	if err != nil {
+		return err
+	}
+
+	return ns.db.SetSync(walletKey(address), bz)
+}
@@ -0,0 +1,274 @@
+package zktx
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestNoteStore(t *testing.T) {
+	SetInitialNote(common.BytesToHash([]byte("sn")), common.BytesToHash([]byte("cmt")))
+
+	db := dbm.NewMemDB()
+	ns := NewNoteStore(db)
+	addr1 := common.BytesToAddress([]byte("addr1"))
+	addr2 := common.BytesToAddress([]byte("addr2"))
+	key1 := DeriveWalletKey([]byte("key1"))
+
+	notes := ns.Lock(addr1)
+	require.Empty(t, notes.Unspent)
+	require.Empty(t, notes.Pending)
+
+	// the notes of another account can be locked concurrently
+	other := ns.Lock(addr2)
+	require.Equal(t, ErrWalletLocked, ns.Save(addr2, other))
+	ns.Unlock(addr2)
+	ns.Unlock(addr1)
+
+	require.NoError(t, ns.Open(addr1, key1))
+	notes = ns.Lock(addr1)
+	sn, cmt, r := common.BytesToHash([]byte{1}), common.BytesToHash([]byte{2}), common.BytesToHash([]byte{3})
+	notes.Unspent = []*Sequence{{SN: &sn, CMT: &cmt, Random: &r, Value: 10}}
+	initial, err := InitializeSN()
+	require.NoError(t, err)
+	notes.AddPending(initial, &Sequence{SN: &r, CMT: &sn, Random: &cmt, Value: 3}, 7)
+	notes.RandomReceiverPK = &GenR().PublicKey
+	received := notes.AddReceived(ReceivedNote{CMTS: cmt, Value: 5})
+	require.Equal(t, received, notes.AddReceived(ReceivedNote{CMTS: cmt}))
+	notes.ScanHeight = 42
+	notes.Track(&Sequence{SN: &r, CMT: &r, Random: &r, Value: 1})
+	require.NoError(t, ns.Save(addr1, notes))
+	ns.Unlock(addr1)
+
+	// the notes are encrypted
+	bz, err := db.Get(walletKey(addr1))
+	require.NoError(t, err)
+	require.NotContains(t, string(bz), string(sn.Bytes()))
+
+	// reload the saved notes
+	reloaded := NewNoteStore(db)
+	require.NoError(t, reloaded.Open(addr1, key1))
+	loaded := reloaded.Lock(addr1)
+	require.Equal(t, notes.Unspent, loaded.Unspent)
+	require.Equal(t, notes.Pending, loaded.Pending)
+	require.Equal(t, crypto.FromECDSAPub(notes.RandomReceiverPK), crypto.FromECDSAPub(loaded.RandomReceiverPK))
+	require.Equal(t, notes.Received, loaded.Received)
+	require.Equal(t, int64(42), loaded.ScanHeight)
+	require.Equal(t, notes.Created, loaded.Created)
+	reloaded.Unlock(addr1)
+
+	// wrong key or record of another account
+	require.Error(t, NewNoteStore(db).Open(addr1, DeriveWalletKey([]byte("key2"))))
+	require.NoError(t, db.Set(walletKey(addr2), bz))
+	require.Error(t, NewNoteStore(db).Open(addr2, key1))
+
+	// in memory only
+	ns = NewNoteStore(nil)
+	require.NoError(t, ns.Save(addr1, ns.Lock(addr1)))
+	ns.Unlock(addr1)
+}
+
+func TestNoteStoreV1(t *testing.T) {
+	SetInitialNote(common.BytesToHash([]byte("sn")), common.BytesToHash([]byte("cmt")))
+
+	db := dbm.NewMemDB()
+	addr := common.BytesToAddress([]byte("addr1"))
+	key := DeriveWalletKey([]byte("key1"))
+
+	// a version 1 record whose last transaction spent a note and created another
+	current, pending := newTestNote(1, 10), newTestNote(2, 7)
+	record := walletRecordV1{
+		Current:    *current,
+		Pending:    *pending,
+		PKBX:       new(big.Int),
+		PKBY:       new(big.Int),
+		Stage:      Origin,
+		Received:   []ReceivedNote{{CMTS: common.BytesToHash([]byte{5}), Value: 5}},
+		ScanHeight: 42,
+	}
+	plaintext, err := rlp.EncodeToBytes(&record)
+	require.NoError(t, err)
+	bz, err := sealNotes(key, addr, plaintext)
+	require.NoError(t, err)
+	bz[0] = walletVersionV1
+	require.NoError(t, db.Set(walletKey(addr), bz))
+
+	ns := NewNoteStore(db)
+	require.NoError(t, ns.Open(addr, key))
+	notes := ns.Lock(addr)
+	require.Equal(t, []*Sequence{current, pending}, notes.Unspent)
+	require.Equal(t, []*Sequence{current, pending}, notes.Created)
+	require.Len(t, notes.Received, 1)
+	require.Equal(t, int64(42), notes.ScanHeight)
+
+	// the chain tells which note the account holds
+	require.NoError(t, notes.Sync([]common.Hash{*pending.CMT}, 43))
+	require.Equal(t, []*Sequence{pending}, notes.Unspent)
+	require.NoError(t, ns.Save(addr, notes))
+	ns.Unlock(addr)
+
+	// and the record is rewritten as version 2
+	bz, err = db.Get(walletKey(addr))
+	require.NoError(t, err)
+	require.Equal(t, byte(walletVersion), bz[0])
+	reloaded := NewNoteStore(db)
+	require.NoError(t, reloaded.Open(addr, key))
+	require.Equal(t, []*Sequence{pending}, reloaded.Lock(addr).Unspent)
+	reloaded.Unlock(addr)
+
+	// the initial note isn't a note of the account
+	initial, err := InitializeSN()
+	require.NoError(t, err)
+	record.Current = *initial
+	plaintext, err = rlp.EncodeToBytes(&record)
+	require.NoError(t, err)
+	notes, err = decodeNotesV1(plaintext)
+	require.NoError(t, err)
+	require.Equal(t, []*Sequence{pending}, notes.Unspent)
+}
+
+func newTestNote(i byte, value uint64) *Sequence {
+	sn, cmt, r := common.BytesToHash([]byte{'s', i}), common.BytesToHash([]byte{'c', i}), common.BytesToHash([]byte{'r', i})
+	return &Sequence{SN: &sn, CMT: &cmt, Random: &r, Value: value}
+}
+
+func TestSelectNote(t *testing.T) {
+	SetInitialNote(common.BytesToHash([]byte("sn")), common.BytesToHash([]byte("cmt")))
+	note1, note2, note3 := newTestNote(1, 10), newTestNote(2, 4), newTestNote(3, 7)
+
+	notes := NewAccountNotes()
+	notes.Unspent = []*Sequence{note1, note2, note3}
+
+	// the smallest note covering the value
+	for _, tc := range []struct {
+		value    uint64
+		expected *Sequence
+	}{
+		{0, note2},
+		{4, note2},
+		{5, note3},
+		{8, note1},
+		{10, note1},
+	} {
+		note, err := notes.SelectNote(tc.value)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, note)
+	}
+	_, err := notes.SelectNote(12)
+	require.Equal(t, ErrNotesFragmented, err)
+	_, err = notes.SelectNote(22)
+	require.Equal(t, ErrInsufficientShieldedBalance, err)
+
+	// the note spent by a pending transaction isn't available
+	notes.AddPending(note3, newTestNote(4, 2), 1)
+	note, err := notes.SelectNote(5)
+	require.NoError(t, err)
+	require.Equal(t, note1, note)
+	confirmed, pending := notes.Balance()
+	require.Equal(t, uint64(21), confirmed)
+	require.Equal(t, uint64(16), pending)
+
+	from, into, ok := notes.SelectMerge()
+	require.True(t, ok)
+	require.Equal(t, note2, from)
+	require.Equal(t, note1, into)
+
+	notes.AddPending(note2, newTestNote(5, 0), 1)
+	_, _, ok = notes.SelectMerge()
+	require.False(t, ok)
+}
+
+func TestSync(t *testing.T) {
+	SetInitialNote(common.BytesToHash([]byte("sn")), common.BytesToHash([]byte("cmt")))
+	initial, err := InitializeSN()
+	require.NoError(t, err)
+	note1, note2, note3, note4 := newTestNote(1, 10), newTestNote(2, 4), newTestNote(3, 7), newTestNote(4, 0)
+
+	notes := NewAccountNotes()
+	notes.Unspent = []*Sequence{note1}
+	notes.Track(note2)
+	notes.AddPending(initial, note2, 10)
+	notes.Track(note3)
+	notes.AddPending(note1, note3, 10)
+
+	// nothing processed yet
+	require.NoError(t, notes.Sync([]common.Hash{*note1.CMT}, 11))
+	require.Equal(t, []*Sequence{note1}, notes.Unspent)
+	require.Len(t, notes.Pending, 2)
+
+	// the mint is processed
+	require.NoError(t, notes.Sync([]common.Hash{*note1.CMT, *note2.CMT}, 12))
+	require.Equal(t, []*Sequence{note1, note2}, notes.Unspent)
+	require.Len(t, notes.Pending, 1)
+	require.Equal(t, []*Sequence{note3}, notes.Created)
+
+	// the send failed
+	require.NoError(t, notes.Sync([]common.Hash{*note1.CMT, *note2.CMT}, 10+pendingTimeout+1))
+	require.Equal(t, []*Sequence{note1, note2}, notes.Unspent)
+	require.Empty(t, notes.Pending)
+	require.Equal(t, []*Sequence{note3}, notes.Created)
+
+	// a zero valued note is unspent but not available
+	notes.AddPending(note2, note4, 30)
+	require.NoError(t, notes.Sync([]common.Hash{*note1.CMT, *note4.CMT}, 31))
+	require.Equal(t, []*Sequence{note1, note4}, notes.Unspent)
+	require.Equal(t, []*Sequence{note1}, notes.Available())
+
+	// a note spent by another wallet
+	notes.AddPending(note1, note3, 32)
+	require.NoError(t, notes.Sync([]common.Hash{*note4.CMT}, 33))
+	require.Equal(t, []*Sequence{note4}, notes.Unspent)
+	require.Empty(t, notes.Pending)
+}
+
+func TestReplay(t *testing.T) {
+	SetInitialNote(common.BytesToHash([]byte("sn")), common.BytesToHash([]byte("cmt")))
+	initial, err := InitializeSN()
+	require.NoError(t, err)
+	note1, note2, note3, note4 := newTestNote(1, 10), newTestNote(2, 4), newTestNote(3, 7), newTestNote(4, 0)
+
+	// the wallet lost track of two mints and a send spending the first one
+	notes := NewAccountNotes()
+	notes.Track(note1)
+	notes.Track(note2)
+	notes.Track(note3)
+	notes.Track(note4)
+	txs := []ShieldedTx{
+		{Spent: *initial.CMT, CMT: *note1.CMT},
+		{Spent: *initial.CMT, CMT: *note2.CMT},
+		{Spent: *note1.CMT, CMT: *note4.CMT},
+	}
+	require.NoError(t, notes.Replay(txs))
+	require.Equal(t, []*Sequence{note2, note4}, notes.Unspent)
+	require.Empty(t, notes.Pending)
+	require.Equal(t, []*Sequence{note3}, notes.Created)
+
+	// replaying again is idempotent, the notes spent since don't need to be known
+	require.NoError(t, notes.Replay(txs))
+	require.Equal(t, []*Sequence{note2, note4}, notes.Unspent)
+
+	// nothing processed yet
+	notes = NewAccountNotes()
+	notes.Track(note1)
+	notes.AddPending(initial, note1, 1)
+	require.NoError(t, notes.Replay(nil))
+	require.Empty(t, notes.Unspent)
+	require.Empty(t, notes.Pending)
+	require.Equal(t, []*Sequence{note1}, notes.Created)
+
+	// an unspent note the wallet never created
+	notes = NewAccountNotes()
+	require.Error(t, notes.Replay(txs[:1]))
+
+	// a transaction that doesn't spend an unspent note
+	notes = NewAccountNotes()
+	notes.Track(note4)
+	require.Error(t, notes.Replay(txs[2:]))
+}
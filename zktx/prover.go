@@ -0,0 +1,82 @@
+package zktx
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrProofGeneration is returned when a circuit fails to generate a proof,
+// usually because the witness doesn't satisfy it.
+var ErrProofGeneration = errors.New("can't generate proof")
+
+// ErrLibsnarkDisabled is returned, or raised as a panic by the proving
+// functions, when the binary was built without the libsnark tag.
+var ErrLibsnarkDisabled = errors.New("zktx: built without libsnark, rebuild with the libsnark build tag")
+
+// Prover generates the zk-SNARK proofs of shielded transactions. Proof generation
+// takes seconds, so RPC servers may delegate it to a separate prover worker.
+type Prover interface {
+	GenMintProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) ([]byte, error)
+	GenSendProof(CMTA *common.Hash, ValueA uint64, RA *common.Hash, ValueS uint64, pk_recv *ecdsa.PublicKey, RS *common.Hash, SNA *common.Hash, CMTS *common.Hash, ValueAnew uint64, SNAnew *common.Hash, RAnew *common.Hash, CMTAnew *common.Hash, SK *common.Hash, pk_sender common.Address) ([]byte, error)
+	GenDepositProof(CMTS *common.Hash, ValueS uint64, SNS *common.Hash, RS *common.Hash, SNA *common.Hash, ValueB uint64, RB *common.Hash, SNBnew *common.Hash, RBnew *common.Hash, pk_recv *ecdsa.PublicKey, RTcmt []byte, CMTB *common.Hash, SNB *common.Hash, CMTBnew *common.Hash, CMTSForMerkle []*common.Hash, SK *common.Hash) ([]byte, error)
+	GenRedeemProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) ([]byte, error)
+}
+
+// NewProver returns a remote prover for the given endpoint, authenticated with
+// token, or the in-process prover if the endpoint is empty.
+func NewProver(endpoint, token string, tlsConfig *tls.Config) (Prover, error) {
+	if endpoint == "" {
+		return LocalProver{}, nil
+	}
+	return NewRemoteProver(endpoint, token, tlsConfig)
+}
+
+var _ Prover = LocalProver{}
+
+// LocalProver generates proofs in-process by calling into libsnark.
+type LocalProver struct{}
+
+// GenMintProof implements Prover.
+func (LocalProver) GenMintProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) ([]byte, error) {
+	if !LibsnarkEnabled {
+		return nil, ErrLibsnarkDisabled
+	}
+	return checkProof(GenMintProof(ValueOld, RAold, SNAnew, RAnew, CMTold, SNold, CMTnew, ValueNew, SK))
+}
+
+// GenSendProof implements Prover.
+func (LocalProver) GenSendProof(CMTA *common.Hash, ValueA uint64, RA *common.Hash, ValueS uint64, pk_recv *ecdsa.PublicKey, RS *common.Hash, SNA *common.Hash, CMTS *common.Hash, ValueAnew uint64, SNAnew *common.Hash, RAnew *common.Hash, CMTAnew *common.Hash, SK *common.Hash, pk_sender common.Address) ([]byte, error) {
+	if !LibsnarkEnabled {
+		return nil, ErrLibsnarkDisabled
+	}
+	return checkProof(GenSendProof(CMTA, ValueA, RA, ValueS, pk_recv, RS, SNA, CMTS, ValueAnew, SNAnew, RAnew, CMTAnew, SK, pk_sender))
+}
+
+// GenDepositProof implements Prover.
+func (LocalProver) GenDepositProof(CMTS *common.Hash, ValueS uint64, SNS *common.Hash, RS *common.Hash, SNA *common.Hash, ValueB uint64, RB *common.Hash, SNBnew *common.Hash, RBnew *common.Hash, pk_recv *ecdsa.PublicKey, RTcmt []byte, CMTB *common.Hash, SNB *common.Hash, CMTBnew *common.Hash, CMTSForMerkle []*common.Hash, SK *common.Hash) ([]byte, error) {
+	if !LibsnarkEnabled {
+		return nil, ErrLibsnarkDisabled
+	}
+	return checkProof(GenDepositProof(CMTS, ValueS, SNS, RS, SNA, ValueB, RB, SNBnew, RBnew, pk_recv, RTcmt, CMTB, SNB, CMTBnew, CMTSForMerkle, SK))
+}
+
+// GenRedeemProof implements Prover.
+func (LocalProver) GenRedeemProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) ([]byte, error) {
+	if !LibsnarkEnabled {
+		return nil, ErrLibsnarkDisabled
+	}
+	return checkProof(GenRedeemProof(ValueOld, RAold, SNAnew, RAnew, CMTold, SNold, CMTnew, ValueNew, SK))
+}
+
+// checkProof returns ErrProofGeneration for the all zero proof the circuits emit
+// when proof generation fails.
+func checkProof(proof []byte) ([]byte, error) {
+	if len(proof) < 10 || strings.HasPrefix(string(proof), "0000000000") {
+		return nil, ErrProofGeneration
+	}
+	return proof, nil
+}
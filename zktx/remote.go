@@ -0,0 +1,372 @@
+package zktx
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Routes served by the prover worker. Each accepts a POST request with the JSON
+// encoded proof request and replies with a ProofResponse.
+const (
+	MintProofRoute    = "/mint"
+	SendProofRoute    = "/send"
+	DepositProofRoute = "/deposit"
+	RedeemProofRoute  = "/redeem"
+)
+
+// DefaultProverTimeout is the time a RemoteProver waits for a proof.
+const DefaultProverTimeout = 5 * time.Minute
+
+var (
+	// ErrInsecureProver is returned for prover endpoints that would send the
+	// witness, spending key included, in plaintext over the network.
+	ErrInsecureProver = errors.New("prover endpoint must use https unless its host is a loopback address")
+
+	// ErrProverToken is returned when no token authenticates the RPC server to
+	// the prover worker.
+	ErrProverToken = errors.New("prover token is empty")
+)
+
+// MintProofRequest holds the witness of a mint or redeem proof.
+type MintProofRequest struct {
+	ValueOld hexutil.Uint64 `json:"valueOld"`
+	RAold    *common.Hash   `json:"rAold"`
+	SNAnew   *common.Hash   `json:"snAnew"`
+	RAnew    *common.Hash   `json:"rAnew"`
+	CMTold   *common.Hash   `json:"cmtOld"`
+	SNold    *common.Hash   `json:"snOld"`
+	CMTnew   *common.Hash   `json:"cmtNew"`
+	ValueNew hexutil.Uint64 `json:"valueNew"`
+	SK       *common.Hash   `json:"sk"`
+}
+
+// SendProofRequest holds the witness of a send proof.
+type SendProofRequest struct {
+	CMTA      *common.Hash   `json:"cmtA"`
+	ValueA    hexutil.Uint64 `json:"valueA"`
+	RA        *common.Hash   `json:"rA"`
+	ValueS    hexutil.Uint64 `json:"valueS"`
+	PKRecv    hexutil.Bytes  `json:"pkRecv"`
+	RS        *common.Hash   `json:"rS"`
+	SNA       *common.Hash   `json:"snA"`
+	CMTS      *common.Hash   `json:"cmtS"`
+	ValueAnew hexutil.Uint64 `json:"valueAnew"`
+	SNAnew    *common.Hash   `json:"snAnew"`
+	RAnew     *common.Hash   `json:"rAnew"`
+	CMTAnew   *common.Hash   `json:"cmtAnew"`
+	SK        *common.Hash   `json:"sk"`
+	PKSender  common.Address `json:"pkSender"`
+}
+
+// DepositProofRequest holds the witness of a deposit proof.
+type DepositProofRequest struct {
+	CMTS          *common.Hash   `json:"cmtS"`
+	ValueS        hexutil.Uint64 `json:"valueS"`
+	SNS           *common.Hash   `json:"snS"`
+	RS            *common.Hash   `json:"rS"`
+	SNA           *common.Hash   `json:"snA"`
+	ValueB        hexutil.Uint64 `json:"valueB"`
+	RB            *common.Hash   `json:"rB"`
+	SNBnew        *common.Hash   `json:"snBnew"`
+	RBnew         *common.Hash   `json:"rBnew"`
+	PKRecv        hexutil.Bytes  `json:"pkRecv"`
+	RTcmt         hexutil.Bytes  `json:"rtCmt"`
+	CMTB          *common.Hash   `json:"cmtB"`
+	SNB           *common.Hash   `json:"snB"`
+	CMTBnew       *common.Hash   `json:"cmtBnew"`
+	CMTSForMerkle []*common.Hash `json:"cmtsForMerkle"`
+	SK            *common.Hash   `json:"sk"`
+}
+
+// ProofResponse is the reply of the prover worker. Exactly one of Proof and
+// Error is set.
+type ProofResponse struct {
+	Proof string `json:"proof,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+var _ Prover = (*RemoteProver)(nil)
+
+// RemoteProver delegates proof generation to a prover worker over HTTP. The
+// circuits take the spending key of the note as part of their witness, so the
+// worker learns it: it must be operated by the same party as the RPC server.
+// Requests are authenticated with a bearer token and sent over TLS, or over
+// plain HTTP to a worker on the same host only.
+type RemoteProver struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+// NewRemoteProver creates a prover sending its requests to the worker listening
+// at endpoint, e.g. "https://prover.example:8547", authenticated with the given
+// token. The worker certificate is verified against tlsConfig, or against the
+// system roots if it is nil. Endpoints without a scheme default to https, and
+// http endpoints are refused unless their host is a loopback address.
+func NewRemoteProver(endpoint, token string, tlsConfig *tls.Config) (*RemoteProver, error) {
+	if token == "" {
+		return nil, ErrProverToken
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !IsLoopbackHost(u.Hostname()) {
+			return nil, fmt.Errorf("%w: %s", ErrInsecureProver, endpoint)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported prover endpoint scheme %q", u.Scheme)
+	}
+
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig = tlsConfig.Clone()
+	if tlsConfig.MinVersion < tls.VersionTLS12 {
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+
+	return &RemoteProver{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		token:    token,
+		client: &http.Client{
+			Timeout:   DefaultProverTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// IsLoopbackHost returns true if host is "localhost" or a loopback IP address.
+func IsLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// LoadProverToken reads the token shared by the RPC servers and the prover
+// worker from a file, ignoring surrounding whitespace.
+func LoadProverToken(path string) (string, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(bz))
+	if token == "" {
+		return "", fmt.Errorf("%s: %w", path, ErrProverToken)
+	}
+	return token, nil
+}
+
+// LoadProverCA returns a TLS configuration trusting the PEM encoded certificates
+// of the given file, for workers whose certificate isn't signed by a system root.
+func LoadProverCA(path string) (*tls.Config, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bz) {
+		return nil, fmt.Errorf("%s: no PEM certificate found", path)
+	}
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
+
+// GenMintProof implements Prover.
+func (p *RemoteProver) GenMintProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) ([]byte, error) {
+	return p.prove(MintProofRoute, newMintProofRequest(ValueOld, RAold, SNAnew, RAnew, CMTold, SNold, CMTnew, ValueNew, SK))
+}
+
+// GenSendProof implements Prover.
+func (p *RemoteProver) GenSendProof(CMTA *common.Hash, ValueA uint64, RA *common.Hash, ValueS uint64, pk_recv *ecdsa.PublicKey, RS *common.Hash, SNA *common.Hash, CMTS *common.Hash, ValueAnew uint64, SNAnew *common.Hash, RAnew *common.Hash, CMTAnew *common.Hash, SK *common.Hash, pk_sender common.Address) ([]byte, error) {
+	return p.prove(SendProofRoute, SendProofRequest{
+		CMTA:      CMTA,
+		ValueA:    hexutil.Uint64(ValueA),
+		RA:        RA,
+		ValueS:    hexutil.Uint64(ValueS),
+		PKRecv:    crypto.FromECDSAPub(pk_recv),
+		RS:        RS,
+		SNA:       SNA,
+		CMTS:      CMTS,
+		ValueAnew: hexutil.Uint64(ValueAnew),
+		SNAnew:    SNAnew,
+		RAnew:     RAnew,
+		CMTAnew:   CMTAnew,
+		SK:        SK,
+		PKSender:  pk_sender,
+	})
+}
+
+// GenDepositProof implements Prover.
+func (p *RemoteProver) GenDepositProof(CMTS *common.Hash, ValueS uint64, SNS *common.Hash, RS *common.Hash, SNA *common.Hash, ValueB uint64, RB *common.Hash, SNBnew *common.Hash, RBnew *common.Hash, pk_recv *ecdsa.PublicKey, RTcmt []byte, CMTB *common.Hash, SNB *common.Hash, CMTBnew *common.Hash, CMTSForMerkle []*common.Hash, SK *common.Hash) ([]byte, error) {
+	return p.prove(DepositProofRoute, DepositProofRequest{
+		CMTS:          CMTS,
+		ValueS:        hexutil.Uint64(ValueS),
+		SNS:           SNS,
+		RS:            RS,
+		SNA:           SNA,
+		ValueB:        hexutil.Uint64(ValueB),
+		RB:            RB,
+		SNBnew:        SNBnew,
+		RBnew:         RBnew,
+		PKRecv:        crypto.FromECDSAPub(pk_recv),
+		RTcmt:         RTcmt,
+		CMTB:          CMTB,
+		SNB:           SNB,
+		CMTBnew:       CMTBnew,
+		CMTSForMerkle: CMTSForMerkle,
+		SK:            SK,
+	})
+}
+
+// GenRedeemProof implements Prover.
+func (p *RemoteProver) GenRedeemProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) ([]byte, error) {
+	return p.prove(RedeemProofRoute, newMintProofRequest(ValueOld, RAold, SNAnew, RAnew, CMTold, SNold, CMTnew, ValueNew, SK))
+}
+
+func (p *RemoteProver) prove(route string, witness interface{}) ([]byte, error) {
+	body, err := json.Marshal(witness)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.endpoint+route, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bz, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var res ProofResponse
+	if err := json.Unmarshal(bz, &res); err != nil {
+		return nil, fmt.Errorf("prover replied with status %s: %w", resp.Status, err)
+	}
+	if res.Error != "" {
+		return nil, errors.New(res.Error)
+	}
+	return checkProof([]byte(res.Proof))
+}
+
+func newMintProofRequest(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) MintProofRequest {
+	return MintProofRequest{
+		ValueOld: hexutil.Uint64(ValueOld),
+		RAold:    RAold,
+		SNAnew:   SNAnew,
+		RAnew:    RAnew,
+		CMTold:   CMTold,
+		SNold:    SNold,
+		CMTnew:   CMTnew,
+		ValueNew: hexutil.Uint64(ValueNew),
+		SK:       SK,
+	}
+}
+
+// NewProverHandler returns the HTTP handler of a prover worker generating the
+// requested proofs with the given prover. Requests not carrying the token as a
+// bearer token are rejected, so the token must not be empty.
+func NewProverHandler(prover Prover, token string) http.Handler {
+	if token == "" {
+		panic(ErrProverToken)
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(MintProofRoute, proofHandler(func() interface{} { return new(MintProofRequest) }, func(req interface{}) ([]byte, error) {
+		r := req.(*MintProofRequest)
+		return prover.GenMintProof(uint64(r.ValueOld), r.RAold, r.SNAnew, r.RAnew, r.CMTold, r.SNold, r.CMTnew, uint64(r.ValueNew), r.SK)
+	}))
+	mux.HandleFunc(RedeemProofRoute, proofHandler(func() interface{} { return new(MintProofRequest) }, func(req interface{}) ([]byte, error) {
+		r := req.(*MintProofRequest)
+		return prover.GenRedeemProof(uint64(r.ValueOld), r.RAold, r.SNAnew, r.RAnew, r.CMTold, r.SNold, r.CMTnew, uint64(r.ValueNew), r.SK)
+	}))
+	mux.HandleFunc(SendProofRoute, proofHandler(func() interface{} { return new(SendProofRequest) }, func(req interface{}) ([]byte, error) {
+		r := req.(*SendProofRequest)
+		pk, err := crypto.UnmarshalPubkey(r.PKRecv)
+		if err != nil {
+			return nil, err
+		}
+		return prover.GenSendProof(r.CMTA, uint64(r.ValueA), r.RA, uint64(r.ValueS), pk, r.RS, r.SNA, r.CMTS, uint64(r.ValueAnew), r.SNAnew, r.RAnew, r.CMTAnew, r.SK, r.PKSender)
+	}))
+	mux.HandleFunc(DepositProofRoute, proofHandler(func() interface{} { return new(DepositProofRequest) }, func(req interface{}) ([]byte, error) {
+		r := req.(*DepositProofRequest)
+		pk, err := crypto.UnmarshalPubkey(r.PKRecv)
+		if err != nil {
+			return nil, err
+		}
+		return prover.GenDepositProof(r.CMTS, uint64(r.ValueS), r.SNS, r.RS, r.SNA, uint64(r.ValueB), r.RB, r.SNBnew, r.RBnew, pk, r.RTcmt, r.CMTB, r.SNB, r.CMTBnew, r.CMTSForMerkle, r.SK)
+	}))
+
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeProofResponse(w, http.StatusUnauthorized, ProofResponse{Error: "unauthorized"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// proofHandler decodes the request into the value returned by newReq and replies
+// with the proof returned by prove.
+func proofHandler(newReq func() interface{}, prove func(req interface{}) ([]byte, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeProofResponse(w, http.StatusMethodNotAllowed, ProofResponse{Error: "method not allowed"})
+			return
+		}
+
+		v := newReq()
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			writeProofResponse(w, http.StatusBadRequest, ProofResponse{Error: err.Error()})
+			return
+		}
+
+		proof, err := prove(v)
+		if err != nil {
+			writeProofResponse(w, http.StatusUnprocessableEntity, ProofResponse{Error: err.Error()})
+			return
+		}
+		writeProofResponse(w, http.StatusOK, ProofResponse{Proof: string(proof)})
+	}
+}
+
+func writeProofResponse(w http.ResponseWriter, status int, res ProofResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
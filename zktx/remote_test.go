@@ -0,0 +1,98 @@
+package zktx
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// echoProver returns a proof derived from the arguments it was given.
+type echoProver struct{}
+
+func (echoProver) GenMintProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) ([]byte, error) {
+	return []byte(CMTnew.Hex()), nil
+}
+
+func (echoProver) GenSendProof(CMTA *common.Hash, ValueA uint64, RA *common.Hash, ValueS uint64, pk_recv *ecdsa.PublicKey, RS *common.Hash, SNA *common.Hash, CMTS *common.Hash, ValueAnew uint64, SNAnew *common.Hash, RAnew *common.Hash, CMTAnew *common.Hash, SK *common.Hash, pk_sender common.Address) ([]byte, error) {
+	return []byte(crypto.PubkeyToAddress(*pk_recv).Hex() + pk_sender.Hex()), nil
+}
+
+func (echoProver) GenDepositProof(CMTS *common.Hash, ValueS uint64, SNS *common.Hash, RS *common.Hash, SNA *common.Hash, ValueB uint64, RB *common.Hash, SNBnew *common.Hash, RBnew *common.Hash, pk_recv *ecdsa.PublicKey, RTcmt []byte, CMTB *common.Hash, SNB *common.Hash, CMTBnew *common.Hash, CMTSForMerkle []*common.Hash, SK *common.Hash) ([]byte, error) {
+	return []byte(CMTSForMerkle[len(CMTSForMerkle)-1].Hex()), nil
+}
+
+func (echoProver) GenRedeemProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) ([]byte, error) {
+	return nil, errors.New("redeem failed")
+}
+
+func TestRemoteProver(t *testing.T) {
+	server := httptest.NewServer(NewProverHandler(echoProver{}, "secret"))
+	defer server.Close()
+
+	prover, err := NewProver(server.URL, "secret", nil)
+	require.NoError(t, err)
+	h := common.HexToHash("0x1234")
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := common.HexToAddress("0x5678")
+
+	proof, err := prover.GenMintProof(1, &h, &h, &h, &h, &h, &h, 2, &h)
+	require.NoError(t, err)
+	require.Equal(t, h.Hex(), string(proof))
+
+	proof, err = prover.GenSendProof(&h, 1, &h, 1, &key.PublicKey, &h, &h, &h, 0, &h, &h, &h, &h, sender)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Hex()+sender.Hex(), string(proof))
+
+	proof, err = prover.GenDepositProof(&h, 1, &h, &h, &h, 0, &h, &h, &h, &key.PublicKey, h[:], &h, &h, &h, []*common.Hash{{}, &h}, &h)
+	require.NoError(t, err)
+	require.Equal(t, h.Hex(), string(proof))
+
+	_, err = prover.GenRedeemProof(1, &h, &h, &h, &h, &h, &h, 0, &h)
+	require.EqualError(t, err, "redeem failed")
+}
+
+func TestRemoteProverSecurity(t *testing.T) {
+	h := common.HexToHash("0x1234")
+
+	// the witness is only sent in plaintext to a worker on the same host
+	_, err := NewRemoteProver("http://10.0.0.1:8547", "secret", nil)
+	require.True(t, errors.Is(err, ErrInsecureProver))
+	_, err = NewRemoteProver("http://localhost:8547", "secret", nil)
+	require.NoError(t, err)
+	_, err = NewRemoteProver("https://localhost:8547", "", nil)
+	require.Equal(t, ErrProverToken, err)
+
+	server := httptest.NewTLSServer(NewProverHandler(echoProver{}, "secret"))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	tlsConfig := &tls.Config{RootCAs: pool}
+
+	prover, err := NewRemoteProver(server.URL, "secret", tlsConfig)
+	require.NoError(t, err)
+	proof, err := prover.GenMintProof(1, &h, &h, &h, &h, &h, &h, 2, &h)
+	require.NoError(t, err)
+	require.Equal(t, h.Hex(), string(proof))
+
+	// requests with another token are rejected
+	prover, err = NewRemoteProver(server.URL, "other", tlsConfig)
+	require.NoError(t, err)
+	_, err = prover.GenMintProof(1, &h, &h, &h, &h, &h, &h, 2, &h)
+	require.EqualError(t, err, "unauthorized")
+
+	// the worker certificate is verified
+	prover, err = NewRemoteProver(server.URL, "secret", nil)
+	require.NoError(t, err)
+	_, err = prover.GenMintProof(1, &h, &h, &h, &h, &h, &h, 2, &h)
+	require.Error(t, err)
+}
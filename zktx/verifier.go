@@ -0,0 +1,194 @@
+package zktx
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cosmos/ethermint/zktx/groth16"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Verifier verifies the zk-SNARK proofs carried by shielded transactions.
+type Verifier interface {
+	VerifyMintProof(cmtold *common.Hash, snaold *common.Hash, cmtnew *common.Hash, value uint64, proof []byte) error
+	VerifySendProof(sna *common.Hash, cmts *common.Hash, proof []byte, cmtAold *common.Hash, cmtAnew *common.Hash) error
+	VerifyDepositProof(pk_recv *ecdsa.PublicKey, rtcmt common.Hash, cmtb *common.Hash, snb *common.Hash, cmtbnew *common.Hash, sns *common.Hash, proof []byte) error
+	VerifyRedeemProof(cmtold *common.Hash, snaold *common.Hash, cmtnew *common.Hash, value uint64, proof []byte) error
+}
+
+// Verifier backend names accepted by NewVerifier.
+const (
+	VerifierLibsnark = "libsnark"
+	VerifierGroth16  = "groth16"
+)
+
+// File names of the verifying keys and of the initial note inside the directory
+// passed to LoadGroth16Verifier.
+const (
+	MintVKFile      = "mint.vk"
+	SendVKFile      = "send.vk"
+	DepositVKFile   = "deposit.vk"
+	RedeemVKFile    = "redeem.vk"
+	InitialNoteFile = "initial_note"
+)
+
+// fieldBits is the number of bits packed into a single public input, the
+// capacity of the BN254 scalar field.
+const fieldBits = 253
+
+// NewVerifier returns the verifier backend with the given name. The groth16
+// backend loads its verifying keys from vkDir.
+func NewVerifier(backend, vkDir string) (Verifier, error) {
+	switch backend {
+	case VerifierLibsnark:
+		if !LibsnarkEnabled {
+			return nil, ErrLibsnarkDisabled
+		}
+		return DefaultVerifier(), nil
+	case VerifierGroth16:
+		return LoadGroth16Verifier(vkDir)
+	default:
+		return nil, fmt.Errorf("unknown zk proof verifier %q, expected %s or %s", backend, VerifierLibsnark, VerifierGroth16)
+	}
+}
+
+var _ Verifier = (*Groth16Verifier)(nil)
+
+// Groth16Verifier verifies Groth16 proofs in pure Go against the verifying keys
+// exported by the circuits, so that validators don't need libsnark to be
+// installed. It is experimental: whether the libsnark circuits are Groth16
+// circuits, their proof and verifying key encoding and the packing of their
+// public inputs haven't been checked against proofs they generated, see
+// testdata/circuits/README.md.
+type Groth16Verifier struct {
+	mint    *groth16.VerifyingKey
+	send    *groth16.VerifyingKey
+	deposit *groth16.VerifyingKey
+	redeem  *groth16.VerifyingKey
+}
+
+// NewGroth16Verifier creates a verifier from the verifying keys of the circuits.
+func NewGroth16Verifier(mint, send, deposit, redeem *groth16.VerifyingKey) *Groth16Verifier {
+	return &Groth16Verifier{
+		mint:    mint,
+		send:    send,
+		deposit: deposit,
+		redeem:  redeem,
+	}
+}
+
+// LoadGroth16Verifier reads the verifying keys of the circuits from dir. If dir
+// also contains an initial note file, holding the serial number and commitment
+// of the initial note as two hex words, the initial note is set from it.
+func LoadGroth16Verifier(dir string) (*Groth16Verifier, error) {
+	var vks [4]*groth16.VerifyingKey
+	for i, name := range []string{MintVKFile, SendVKFile, DepositVKFile, RedeemVKFile} {
+		bz, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if vks[i], err = groth16.ParseVerifyingKey(bz); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	bz, err := ioutil.ReadFile(filepath.Join(dir, InitialNoteFile))
+	switch {
+	case err == nil:
+		fields := strings.Fields(string(bz))
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s: expected serial number and commitment, got %d fields", InitialNoteFile, len(fields))
+		}
+		SetInitialNote(common.HexToHash(fields[0]), common.HexToHash(fields[1]))
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	return NewGroth16Verifier(vks[0], vks[1], vks[2], vks[3]), nil
+}
+
+// VerifyMintProof implements Verifier.
+func (v *Groth16Verifier) VerifyMintProof(cmtold *common.Hash, snaold *common.Hash, cmtnew *common.Hash, value uint64, proof []byte) error {
+	if err := verify(v.mint, proof, cmtold[:], snaold[:], cmtnew[:], uint64Bytes(value)); err != nil {
+		return InvalidMintProof
+	}
+	return nil
+}
+
+// VerifySendProof implements Verifier.
+func (v *Groth16Verifier) VerifySendProof(sna *common.Hash, cmts *common.Hash, proof []byte, cmtAold *common.Hash, cmtAnew *common.Hash) error {
+	if err := verify(v.send, proof, cmtAold[:], sna[:], cmts[:], cmtAnew[:]); err != nil {
+		return InvalidSendProof
+	}
+	return nil
+}
+
+// VerifyDepositProof implements Verifier.
+func (v *Groth16Verifier) VerifyDepositProof(pk_recv *ecdsa.PublicKey, rtcmt common.Hash, cmtb *common.Hash, snb *common.Hash, cmtbnew *common.Hash, sns *common.Hash, proof []byte) error {
+	PK_recv := crypto.PubkeyToAddress(*pk_recv)
+	if err := verify(v.deposit, proof, rtcmt[:], PK_recv[:], cmtb[:], snb[:], cmtbnew[:], sns[:]); err != nil {
+		return InvalidDepositProof
+	}
+	return nil
+}
+
+// VerifyRedeemProof implements Verifier.
+func (v *Groth16Verifier) VerifyRedeemProof(cmtold *common.Hash, snaold *common.Hash, cmtnew *common.Hash, value uint64, proof []byte) error {
+	if err := verify(v.redeem, proof, cmtold[:], snaold[:], cmtnew[:], uint64Bytes(value)); err != nil {
+		return InvalidRedeemProof
+	}
+	return nil
+}
+
+func verify(vk *groth16.VerifyingKey, proof []byte, inputs ...[]byte) error {
+	p, err := groth16.ParseProof(proof)
+	if err != nil {
+		return err
+	}
+	return groth16.Verify(vk, p, PackInputs(inputs...))
+}
+
+// PackInputs packs the public inputs of a circuit into field elements the way
+// libsnark's multipacking gadget is assumed to: the inputs are concatenated into
+// a single bit string, most significant bit of every byte first, which is then
+// cut into 253 bit chunks with the first bit of a chunk being its least
+// significant bit. The packing hasn't been checked against the circuits.
+func PackInputs(inputs ...[]byte) []*big.Int {
+	var bits []uint
+	for _, input := range inputs {
+		for _, b := range input {
+			for i := 7; i >= 0; i-- {
+				bits = append(bits, uint(b>>uint(i))&1)
+			}
+		}
+	}
+
+	var packed []*big.Int
+	for start := 0; start < len(bits); start += fieldBits {
+		end := start + fieldBits
+		if end > len(bits) {
+			end = len(bits)
+		}
+
+		x := new(big.Int)
+		for i, bit := range bits[start:end] {
+			x.SetBit(x, i, bit)
+		}
+		packed = append(packed, x)
+	}
+	return packed
+}
+
+func uint64Bytes(value uint64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, value)
+	return bz
+}
@@ -0,0 +1,93 @@
+//go:build circuits
+// +build circuits
+
+package zktx
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// circuitFixtures is the directory holding the verifying keys exported by the
+// libsnark circuits together with proofs.json, proofs generated by a libsnark
+// build and their public inputs. See testdata/circuits/README.md.
+const circuitFixtures = "testdata/circuits"
+
+type noteFixture struct {
+	CMTOld common.Hash    `json:"cmtOld"`
+	SNOld  common.Hash    `json:"snOld"`
+	CMTNew common.Hash    `json:"cmtNew"`
+	Value  hexutil.Uint64 `json:"value"`
+	Proof  string         `json:"proof"`
+}
+
+type sendFixture struct {
+	SN     common.Hash `json:"sn"`
+	CMTS   common.Hash `json:"cmts"`
+	CMTOld common.Hash `json:"cmtOld"`
+	CMTNew common.Hash `json:"cmtNew"`
+	Proof  string      `json:"proof"`
+}
+
+type depositFixture struct {
+	PubKey hexutil.Bytes `json:"pubKey"`
+	RT     common.Hash   `json:"rt"`
+	CMTOld common.Hash   `json:"cmtOld"`
+	SN     common.Hash   `json:"sn"`
+	CMTNew common.Hash   `json:"cmtNew"`
+	SNS    common.Hash   `json:"sns"`
+	Proof  string        `json:"proof"`
+}
+
+type proofFixtures struct {
+	Mint    *noteFixture    `json:"mint"`
+	Send    *sendFixture    `json:"send"`
+	Deposit *depositFixture `json:"deposit"`
+	Redeem  *noteFixture    `json:"redeem"`
+}
+
+// TestGroth16VerifierFixtures checks the proof and verifying key encodings and
+// the packing of the public inputs against the real circuits. It only runs with
+// the circuits build tag and fails if the fixtures exported from libsnark are
+// missing.
+func TestGroth16VerifierFixtures(t *testing.T) {
+	bz, err := ioutil.ReadFile(filepath.Join(circuitFixtures, "proofs.json"))
+	require.NoError(t, err, "no libsnark fixtures in %s", circuitFixtures)
+
+	var fixtures proofFixtures
+	require.NoError(t, json.Unmarshal(bz, &fixtures))
+	v, err := LoadGroth16Verifier(circuitFixtures)
+	require.NoError(t, err)
+
+	// every proof verifies, and no longer does once its last public input changes
+	if f := fixtures.Mint; f != nil {
+		require.NoError(t, v.VerifyMintProof(&f.CMTOld, &f.SNOld, &f.CMTNew, uint64(f.Value), []byte(f.Proof)))
+		require.Error(t, v.VerifyMintProof(&f.CMTOld, &f.SNOld, &f.CMTNew, uint64(f.Value)+1, []byte(f.Proof)))
+	}
+	if f := fixtures.Send; f != nil {
+		require.NoError(t, v.VerifySendProof(&f.SN, &f.CMTS, []byte(f.Proof), &f.CMTOld, &f.CMTNew))
+		tampered := f.CMTNew
+		tampered[common.HashLength-1] ^= 1
+		require.Error(t, v.VerifySendProof(&f.SN, &f.CMTS, []byte(f.Proof), &f.CMTOld, &tampered))
+	}
+	if f := fixtures.Deposit; f != nil {
+		pub, err := crypto.UnmarshalPubkey(f.PubKey)
+		require.NoError(t, err)
+		require.NoError(t, v.VerifyDepositProof(pub, f.RT, &f.CMTOld, &f.SN, &f.CMTNew, &f.SNS, []byte(f.Proof)))
+		tampered := f.SNS
+		tampered[common.HashLength-1] ^= 1
+		require.Error(t, v.VerifyDepositProof(pub, f.RT, &f.CMTOld, &f.SN, &f.CMTNew, &tampered, []byte(f.Proof)))
+	}
+	if f := fixtures.Redeem; f != nil {
+		require.NoError(t, v.VerifyRedeemProof(&f.CMTOld, &f.SNOld, &f.CMTNew, uint64(f.Value), []byte(f.Proof)))
+		require.Error(t, v.VerifyRedeemProof(&f.CMTOld, &f.SNOld, &f.CMTNew, uint64(f.Value)+1, []byte(f.Proof)))
+	}
+}
@@ -0,0 +1,229 @@
+package zktx
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// walletVersion is the version byte prefixed to the encrypted wallet records.
+// Version 2 records hold a set of unspent notes instead of a single note.
+// Version 1 records are still read, and rewritten as version 2 on next save.
+const (
+	walletVersion   = 2
+	walletVersionV1 = 1
+)
+
+// ErrWalletLocked is returned when saving the notes of an account whose wallet
+// key is unknown.
+var ErrWalletLocked = errors.New("shielded wallet of the account is locked")
+
+// ReceivedNote is a note sent to an account, decrypted from the AUX of the send
+// transaction. Key is the address of the one-time key the note is sent to,
+// which the deposit transaction of the note reveals.
+type ReceivedNote struct {
+	TxHash    common.Hash
+	CMTS      common.Hash
+	Value     uint64
+	RS        common.Hash
+	SNA       common.Hash
+	Key       common.Address
+	Deposited bool
+}
+
+// DeriveWalletKey derives the key encrypting the shielded wallet of an account
+// from its private key.
+func DeriveWalletKey(privKey []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("zktx wallet"))
+	h.Write(privKey)
+	return h.Sum(nil)
+}
+
+// walletKey returns the key of the wallet record of an account.
+func walletKey(address common.Address) []byte {
+	return append([]byte("notes/"), address.Bytes()...)
+}
+
+// walletRecord is the persisted form of the notes of an account.
+type walletRecord struct {
+	Unspent    []Sequence
+	Pending    []pendingRecord
+	SNS        *Sequence `rlp:"nil"`
+	PKBX       *big.Int
+	PKBY       *big.Int
+	Received   []ReceivedNote
+	ScanHeight uint64
+	Created    []Sequence
+}
+
+type pendingRecord struct {
+	Spent   Sequence
+	Created Sequence
+	Height  uint64
+}
+
+// walletRecordV1 is the persisted form of the notes of an account in version 1
+// records, which held the note spent by the last shielded transaction of the
+// account and the note it created.
+type walletRecordV1 struct {
+	Current    Sequence
+	Pending    Sequence
+	SNS        *Sequence `rlp:"nil"`
+	PKBX       *big.Int
+	PKBY       *big.Int
+	Stage      uint8
+	Received   []ReceivedNote
+	ScanHeight uint64
+	Created    []Sequence
+}
+
+func encodeNotes(notes *AccountNotes) ([]byte, error) {
+	record := walletRecord{
+		SNS:        notes.SNS,
+		PKBX:       new(big.Int),
+		PKBY:       new(big.Int),
+		ScanHeight: uint64(notes.ScanHeight),
+	}
+	if notes.RandomReceiverPK != nil {
+		record.PKBX, record.PKBY = notes.RandomReceiverPK.X, notes.RandomReceiverPK.Y
+	}
+	for _, note := range notes.Unspent {
+		record.Unspent = append(record.Unspent, *note)
+	}
+	for _, tx := range notes.Pending {
+		record.Pending = append(record.Pending, pendingRecord{Spent: *tx.Spent, Created: *tx.Created, Height: uint64(tx.Height)})
+	}
+	for _, note := range notes.Received {
+		record.Received = append(record.Received, *note)
+	}
+	for _, note := range notes.Created {
+		record.Created = append(record.Created, *note)
+	}
+	return rlp.EncodeToBytes(&record)
+}
+
+// decodeNotesV1 migrates a version 1 record. Whether the last transaction of
+// the account was processed isn't known, so both its spent and created notes
+// are taken as unspent, and the next Sync keeps the one that is on chain. They
+// are also tracked, so that Replay can recover them.
+func decodeNotesV1(bz []byte) (*AccountNotes, error) {
+	var record walletRecordV1
+	if err := rlp.DecodeBytes(bz, &record); err != nil {
+		return nil, err
+	}
+
+	_, initialCMT, err := InitialNote()
+	if err != nil {
+		return nil, err
+	}
+
+	notes := &AccountNotes{
+		SNS:        record.SNS,
+		ScanHeight: int64(record.ScanHeight),
+	}
+	if record.PKBX.Sign() != 0 || record.PKBY.Sign() != 0 {
+		notes.RandomReceiverPK = &ecdsa.PublicKey{Curve: crypto.S256(), X: record.PKBX, Y: record.PKBY}
+	}
+	for i := range record.Created {
+		notes.Created = append(notes.Created, &record.Created[i])
+	}
+	for _, note := range []*Sequence{&record.Current, &record.Pending} {
+		if note.CMT == nil || *note.CMT == initialCMT {
+			continue
+		}
+		if len(notes.Unspent) > 0 && *notes.Unspent[0].CMT == *note.CMT {
+			continue
+		}
+		notes.Unspent = append(notes.Unspent, note)
+		notes.untrack(note)
+		notes.Track(note)
+	}
+	for i := range record.Received {
+		notes.Received = append(notes.Received, &record.Received[i])
+	}
+	return notes, nil
+}
+
+func decodeNotes(bz []byte) (*AccountNotes, error) {
+	var record walletRecord
+	if err := rlp.DecodeBytes(bz, &record); err != nil {
+		return nil, err
+	}
+
+	notes := &AccountNotes{
+		SNS:        record.SNS,
+		ScanHeight: int64(record.ScanHeight),
+	}
+	if record.PKBX.Sign() != 0 || record.PKBY.Sign() != 0 {
+		notes.RandomReceiverPK = &ecdsa.PublicKey{Curve: crypto.S256(), X: record.PKBX, Y: record.PKBY}
+	}
+	for i := range record.Unspent {
+		notes.Unspent = append(notes.Unspent, &record.Unspent[i])
+	}
+	for i := range record.Pending {
+		tx := &record.Pending[i]
+		notes.Pending = append(notes.Pending, &PendingTx{Spent: &tx.Spent, Created: &tx.Created, Height: int64(tx.Height)})
+	}
+	for i := range record.Received {
+		notes.Received = append(notes.Received, &record.Received[i])
+	}
+	for i := range record.Created {
+		notes.Created = append(notes.Created, &record.Created[i])
+	}
+	return notes, nil
+}
+
+// sealNotes encrypts the encoded notes of an account with AES-GCM. The address
+// is authenticated so that records can't be swapped between accounts.
+func sealNotes(key []byte, address common.Address, plaintext []byte) ([]byte, error) {
+	aead, err := newWalletCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte{walletVersion}, nonce...)
+	return aead.Seal(out, nonce, plaintext, address.Bytes()), nil
+}
+
+// openNotes decrypts a record sealed by sealNotes and returns its version.
+func openNotes(key []byte, address common.Address, bz []byte) (byte, []byte, error) {
+	aead, err := newWalletCipher(key)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(bz) < 1+aead.NonceSize() {
+		return 0, nil, errors.New("wallet record too short")
+	}
+	if bz[0] != walletVersion && bz[0] != walletVersionV1 {
+		return 0, nil, fmt.Errorf("unknown wallet record version %d", bz[0])
+	}
+
+	nonce, ciphertext := bz[1:1+aead.NonceSize()], bz[1+aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, address.Bytes())
+	return bz[0], plaintext, err
+}
+
+func newWalletCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -1,27 +1,14 @@
 package zktx
 
-/*
-#cgo LDFLAGS: -L/usr/local/lib -lzk_mint  -lzk_send  -lzk_deposit -lzk_redeem -lff  -lsnark -lstdc++  -lgmp -lgmpxx
-#include "mintcgo.hpp"
-#include "sendcgo.hpp"
-#include "depositcgo.hpp"
-#include "redeemcgo.hpp"
-#include <stdlib.h>
-*/
-import "C"
 import (
-	"bytes"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"math/big"
-	"os"
 	"sync"
-	"unsafe"
 
 	"github.com/cosmos/ethermint/crypto/ecies"
 
@@ -37,20 +24,6 @@ type Sequence struct {
 	Random *common.Hash
 	Value  uint64
 	Valid  bool
-	Lock   sync.Mutex
-}
-
-type WriteSn struct {
-	SNumber      *Sequence
-	SNumberAfter *Sequence
-}
-type SequenceS struct {
-	Suquence1 Sequence
-	Suquence2 Sequence
-	SNS       *Sequence
-	PKBX      *big.Int
-	PKBY      *big.Int
-	Stage     uint8
 }
 
 const (
@@ -62,34 +35,63 @@ const (
 	Redeem
 )
 
-var SNfile *os.File
-var FileLine uint8
-
-var Stage uint8
 var ZKTxAddress = common.HexToAddress("ffffffffffffffffffffffffffffffffffffffff")
-var SequenceNumber = InitializeSN()                //--zy
-var SequenceNumberAfter *Sequence = InitializeSN() //--zy
-var SNS *Sequence = nil
+
+// InitialSK is the key of the initial note. The initial note holds no value, so
+// its key is public and every account can start from the same note.
+var InitialSK = ZKTxAddress.Hash()
 
 var ZKCMTNODES = 1 // max is 32  because of merkle leaves in libnsark is 32
 
 var ErrSequence = errors.New("invalid sequence")
-var RandomReceiverPK *ecdsa.PublicKey = nil
 
+// ErrInitialNoteUnknown is returned when the initial note has neither been
+// computed by libsnark nor set through SetInitialNote.
+var ErrInitialNoteUnknown = errors.New("initial shielded note unknown: build with libsnark or provide it with the verifying keys")
+
+var (
+	initialNoteLock sync.RWMutex
+	initialSN       *common.Hash
+	initialCMT      *common.Hash
+)
+
+// SetInitialNote sets the serial number and commitment of the zero valued note
+// that every account holds before its first shielded transaction. Builds with
+// libsnark compute it on startup, builds without it must be given the values.
+func SetInitialNote(sn, cmt common.Hash) {
+	initialNoteLock.Lock()
+	defer initialNoteLock.Unlock()
+
+	initialSN = &sn
+	initialCMT = &cmt
+}
+
+// InitialNote returns the serial number and commitment of the zero valued note
+// that every account holds before its first shielded transaction. It returns
+// ErrInitialNoteUnknown if the note is not known.
+func InitialNote() (sn, cmt common.Hash, err error) {
+	initialNoteLock.RLock()
+	defer initialNoteLock.RUnlock()
 
-func InitializeSN() *Sequence {
-	// For large-scale test, we suppose that SK = CRH(addr), there is impossible in pratical.
-	SK := ZKTxAddress.Hash()
-	r := &common.Hash{}
-	sn := ComputePRF(SK.Bytes(), r.Bytes()) // sn = PRF(sk, r)
+	if initialSN == nil || initialCMT == nil {
+		return common.Hash{}, common.Hash{}, ErrInitialNoteUnknown
+	}
+	return *initialSN, *initialCMT, nil
+}
 
-	cmt := GenCMT(0, sn.Bytes(), r.Bytes())
+// InitializeSN returns the initial note, spent with InitialSK by the first
+// shielded transaction of an account.
+func InitializeSN() (*Sequence, error) {
+	sn, cmt, err := InitialNote()
+	if err != nil {
+		return nil, err
+	}
 	return &Sequence{
-		SN:     sn,
-		CMT:    cmt,
-		Random: r,
+		SN:     &sn,
+		CMT:    &cmt,
+		Random: &common.Hash{},
 		Value:  0,
-	}
+	}, nil
 }
 
 func NewRandomHash() *common.Hash {
@@ -114,246 +116,85 @@ func NewRandomInt() *big.Int {
 	return r
 }
 
-// func VerifyMintProof(cmtold *common.Hash, snaold *common.Hash, cmtnew *common.Hash, value uint64, balance uint64, proof []byte) error {
-// 	return nil
-// }
-
 var InvalidMintProof = errors.New("Verifying mint proof failed!!!")
-
-func VerifyMintProof(cmtold *common.Hash, snaold *common.Hash, cmtnew *common.Hash, value uint64, proof []byte) error {
-	cproof := C.CString(string(proof))
-	cmtA_old_c := C.CString(common.ToHex(cmtold[:]))
-	cmtA_c := C.CString(common.ToHex(cmtnew[:]))
-	sn_old_c := C.CString(common.ToHex(snaold.Bytes()[:]))
-	value_s_c := C.ulong(value)
-	tf := C.verifyMintproof(cproof, cmtA_old_c, sn_old_c, cmtA_c, value_s_c)
-	if tf == false {
-		return InvalidMintProof
-	}
-	return nil
-}
-
 var InvalidSendProof = errors.New("Verifying send proof failed!!!")
-
-// func VerifySendProof(sna *common.Hash, cmts *common.Hash, proof []byte) error {
-// 	cproof := C.CString(string(proof))
-// 	snA := C.CString(common.ToHex(sna.Bytes()[:]))
-// 	cmtS := C.CString(common.ToHex(cmts[:]))
-
-// 	tf := C.verifySendproof(cproof, snA, cmtS)
-// 	if tf == false {
-// 		return InvalidSendProof
-// 	}
-// 	return nil
-// }
-func VerifySendProof(sna *common.Hash, cmts *common.Hash, proof []byte, cmtAold *common.Hash, cmtAnew *common.Hash) error {
-	cproof := C.CString(string(proof))
-	snAold_c := C.CString(common.ToHex(sna.Bytes()[:]))
-	cmtS := C.CString(common.ToHex(cmts[:]))
-	cmtAold_c := C.CString(common.ToHex(cmtAold[:]))
-	cmtAnew_c := C.CString(common.ToHex(cmtAnew[:]))
-
-	tf := C.verifySendproof(cproof, cmtAold_c, snAold_c, cmtS, cmtAnew_c)
-	if tf == false {
-		return InvalidSendProof
-	}
-	return nil
-}
-
 var InvalidUpdateProof = errors.New("Verifying update proof failed!!!")
-
-//func VerifyUpdateProof(cmta *common.Hash, rtmcmt []byte, cmtnew *common.Hash, proof []byte) error {
-// func VerifyUpdateProof(cmta *common.Hash, rtmcmt common.Hash, cmtnew *common.Hash, proof []byte) error {
-// 	cproof := C.CString(string(proof))
-// 	rtmCmt := C.CString(common.ToHex(rtmcmt[:]))
-// 	cmtA := C.CString(common.ToHex(cmta[:]))
-// 	cmtAnew := C.CString(common.ToHex(cmtnew[:]))
-
-// 	tf := C.verifyUpdateproof(cproof, rtmCmt, cmtA, cmtAnew)
-// 	if tf == false {
-// 		return InvalidUpdateProof
-// 	}
-// 	return nil
-// }
-
 var InvalidDepositProof = errors.New("Verifying Deposit proof failed!!!")
-
-func VerifyDepositProof(pk_recv *ecdsa.PublicKey, rtcmt common.Hash, cmtb *common.Hash, snb *common.Hash, cmtbnew *common.Hash, sns *common.Hash, proof []byte) error {
-	PK_recv := crypto.PubkeyToAddress(*pk_recv) //--zy
-	pk_recv_c := C.CString(common.ToHex(PK_recv[:]))
-	cproof := C.CString(string(proof))
-	rtmCmt := C.CString(common.ToHex(rtcmt[:]))
-	cmtB := C.CString(common.ToHex(cmtb[:]))
-	cmtBnew := C.CString(common.ToHex(cmtbnew[:]))
-	SNB_c := C.CString(common.ToHex(snb.Bytes()[:]))
-	SNS_c := C.CString(common.ToHex(sns.Bytes()[:]))
-	tf := C.verifyDepositproof(cproof, rtmCmt, pk_recv_c, cmtB, SNB_c, cmtBnew, SNS_c)
-	if tf == false {
-		return InvalidDepositProof
-	}
-	return nil
-}
-
 var InvalidRedeemProof = errors.New("Verifying redeem proof failed!!!")
 
-func VerifyRedeemProof(cmtold *common.Hash, snaold *common.Hash, cmtnew *common.Hash, value uint64, proof []byte) error {
-	cproof := C.CString(string(proof))
-	cmtA_old_c := C.CString(common.ToHex(cmtold[:]))
-	cmtA_c := C.CString(common.ToHex(cmtnew[:]))
-	sn_old_c := C.CString(common.ToHex(snaold.Bytes()[:]))
-	value_s_c := C.ulong(value)
-
-	tf := C.verifyRedeemproof(cproof, cmtA_old_c, sn_old_c, cmtA_c, value_s_c)
-	if tf == false {
-		return InvalidRedeemProof
-	}
-	return nil
-}
-
 func VerifyDepositSIG(x *big.Int, y *big.Int, sig []byte) error {
 	return nil
 }
 
-//GenCMT生成CMT 调用c的sha256函数  （go的sha256函数与c有一些区别）
-func GenCMT(value uint64, sn []byte, r []byte) *common.Hash {
-	//sn_old_c := C.CString(common.ToHex(SNold[:]))
-	value_c := C.ulong(value)
-	sn_string := common.ToHex(sn[:])
-	sn_c := C.CString(sn_string)
-	defer C.free(unsafe.Pointer(sn_c))
-	r_string := common.ToHex(r[:])
-	r_c := C.CString(r_string)
-	defer C.free(unsafe.Pointer(r_c))
-
-	cmtA_c := C.genCMT(value_c, sn_c, r_c)
-	cmtA_go := C.GoString(cmtA_c)
-	//res := []byte(cmtA_go)
-	res, _ := hex.DecodeString(cmtA_go)
-	reshash := common.BytesToHash(res)
-	return &reshash
-}
-
-//GenCMT生成CMT 调用c的sha256函数  （go的sha256函数与c有一些区别）
-func GenCMTS(values uint64, pk_recv *ecdsa.PublicKey, rs []byte, sna []byte) *common.Hash {
-	values_c := C.ulong(values)
-	PK_recv := crypto.PubkeyToAddress(*pk_recv) //--zy
-	pk_recv_c := C.CString(common.ToHex(PK_recv[:]))
-	rs_string := common.ToHex(rs[:])
-	rs_c := C.CString(rs_string)
-	defer C.free(unsafe.Pointer(rs_c))
-	sna_string := common.ToHex(sna[:])
-	sna_c := C.CString(sna_string)
-	defer C.free(unsafe.Pointer(sna_c))
-	//uint64_t value_s,char* pk_string,char* sn_s_string,char* r_s_string,char *sn_old_string
-	cmtA_c := C.genCMTS(values_c, pk_recv_c, rs_c, sna_c) //64长度16进制数
-	cmtA_go := C.GoString(cmtA_c)
-	//res := []byte(cmtA_go)
-	res, _ := hex.DecodeString(cmtA_go)
-	reshash := common.BytesToHash(res) //32长度byte数组
-	return &reshash
-}
-
-//ComputePRF生成sn 调用c的sha256函数  （go的sha256函数与c有一些区别）
-func ComputePRF(sk []byte, r []byte) *common.Hash {
-	addr_string := common.ToHex(sk[:])
-	addr_c := C.CString(addr_string)
-	defer C.free(unsafe.Pointer(addr_c))
-
-	r_string := common.ToHex(r[:])
-	r_c := C.CString(r_string)
-	defer C.free(unsafe.Pointer(r_c))
-
-	sn_c := C.computePRF(addr_c, r_c)
-	sn_go := C.GoString(sn_c)
-	//res := []byte(cmtA_go)
-	res, _ := hex.DecodeString(sn_go)
-	reshash := common.BytesToHash(res)
-	return &reshash
-}
-
-//ComputeCRH生成r_s 调用c的sha256函数  （go的sha256函数与c有一些区别）
-func ComputeCRH(pk_recv common.Address, r []byte) *common.Hash {
-	//PK_recv := crypto.PubkeyToAddress(*pk_recv) //--zy
-	pk_recv_c := C.CString(common.ToHex(pk_recv[:]))
-
-	r_string := common.ToHex(r[:])
-	r_c := C.CString(r_string)
-	defer C.free(unsafe.Pointer(r_c))
-
-	r_s_c := C.computeCRH(pk_recv_c, r_c)
-	r_s_go := C.GoString(r_s_c)
-	//res := []byte(cmtA_go)
-	res, _ := hex.DecodeString(r_s_go)
-	reshash := common.BytesToHash(res)
-	return &reshash
-}
-
-//GenRT 返回merkel树的hash  --zy
-func GenRT(CMTSForMerkle []*common.Hash) common.Hash {
-	var cmtArray string
-	for i := 0; i < len(CMTSForMerkle); i++ {
-		s := string(common.ToHex(CMTSForMerkle[i][:]))
-		cmtArray += s
-	}
-	cmtsM := C.CString(cmtArray)
-	rtC := C.genRoot(cmtsM, C.int(len(CMTSForMerkle))) //--zy
-	rtGo := C.GoString(rtC)
-
-	res, _ := hex.DecodeString(rtGo)   //返回32长度 []byte  一个byte代表两位16进制数
-	reshash := common.BytesToHash(res) //32长度byte数组
-	return reshash
-}
-
 func ComputeR(sk *big.Int) *ecdsa.PublicKey {
 	return &ecdsa.PublicKey{} //tbd
 }
 
-func Encrypt(pub *ecdsa.PublicKey, m []byte) ([]byte, error) {
-	P := ecies.ImportECDSAPublic(pub)
-	ke := P.X.Bytes()
-	ke = ke[:16]
-	ct, err := ecies.SymEncrypt(rand.Reader, P.Params, ke, m)
+// auxVersion is the version byte prefixed to the encrypted AUX.
+const auxVersion = 1
 
-	return ct, err
+// Encrypt encrypts the message to the public key with ECIES, using an ephemeral
+// key and authenticating the ciphertext together with the shared info.
+func Encrypt(pub *ecdsa.PublicKey, m, shared []byte) ([]byte, error) {
+	return ecies.Encrypt(rand.Reader, ecies.ImportECDSAPublic(pub), m, nil, shared)
 }
 
-func Decrypt(pub *ecdsa.PublicKey, ct []byte) ([]byte, error) {
-	P := ecies.ImportECDSAPublic(pub)
-	ke := P.X.Bytes()
-	ke = ke[:16]
-	m, err := ecies.SymDecrypt(P.Params, ke, ct)
-	return m, err
+// Decrypt decrypts a ciphertext of Encrypt with the private key.
+func Decrypt(prv *ecdsa.PrivateKey, ct, shared []byte) ([]byte, error) {
+	return ecies.ImportECDSA(prv).Decrypt(ct, nil, shared)
 }
 
 type AUX struct {
 	Value uint64
 	//SNs   *common.Hash
-	Rs    *common.Hash
-	SNa   *common.Hash
+	Rs  *common.Hash
+	SNa *common.Hash
 }
 
-func ComputeAUX(randomReceiverPK *ecdsa.PublicKey, value uint64, Rs *common.Hash, SNa *common.Hash) []byte {
+// ComputeAUX encrypts the value and randomness of a send commitment to the
+// one-time public key of its receiver. The AUX is prefixed with its version,
+// which is authenticated with the ciphertext.
+func ComputeAUX(randomReceiverPK *ecdsa.PublicKey, value uint64, Rs *common.Hash, SNa *common.Hash) ([]byte, error) {
 	aux := AUX{
 		Value: value,
 		Rs:    Rs,
 		SNa:   SNa,
 	}
-	bytes, _ := rlp.EncodeToBytes(aux)
-	encbytes, _ := Encrypt(randomReceiverPK, bytes)
-	return encbytes
+	bz, err := rlp.EncodeToBytes(aux)
+	if err != nil {
+		return nil, err
+	}
+
+	ct, err := Encrypt(randomReceiverPK, bz, []byte{auxVersion})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{auxVersion}, ct...), nil
 }
 
-func DecAUX(key *ecdsa.PublicKey, data []byte) (uint64, *common.Hash, *common.Hash) {
-	decdata, _ := Decrypt(key, data)
-	aux := AUX{}
-	r := bytes.NewReader(decdata)
+// DecAUX decrypts an AUX of ComputeAUX with the one-time private key of the
+// receiver. It fails if the AUX wasn't encrypted to the key.
+func DecAUX(key *ecdsa.PrivateKey, data []byte) (uint64, *common.Hash, *common.Hash, error) {
+	if len(data) == 0 {
+		return 0, nil, nil, errors.New("empty AUX")
+	}
+	if data[0] != auxVersion {
+		return 0, nil, nil, fmt.Errorf("unknown AUX version %d", data[0])
+	}
+
+	bz, err := Decrypt(key, data[1:], data[:1])
+	if err != nil {
+		return 0, nil, nil, err
+	}
 
-	s := rlp.NewStream(r, 96)
-	if err := s.Decode(&aux); err != nil {
-		fmt.Println("Decode aux error: ", err)
-		return 0, nil, nil
+	var aux AUX
+	if err := rlp.DecodeBytes(bz, &aux); err != nil {
+		return 0, nil, nil, err
 	}
-	return aux.Value, aux.Rs, aux.SNa
+	if aux.Rs == nil || aux.SNa == nil {
+		return 0, nil, nil, errors.New("incomplete AUX")
+	}
+	return aux.Value, aux.Rs, aux.SNa, nil
 }
 
 func GenerateKeyForRandomB(R *ecdsa.PublicKey, kB *ecdsa.PrivateKey) *ecdsa.PrivateKey {
@@ -376,151 +217,11 @@ func GenerateKeyForRandomB(R *ecdsa.PublicKey, kB *ecdsa.PrivateKey) *ecdsa.Priv
 	sskB := new(ecdsa.PrivateKey)
 	sskB.PublicKey.X, sskB.PublicKey.Y = c.Add(sx, sy, kB.PublicKey.X, kB.PublicKey.Y)
 	sskB.Curve = c
-	//生成私钥
-	sskB.D = i.Add(i, kB.D)
+	//生成私钥, reduced modulo the curve order to stay a valid scalar
+	sskB.D = i.Mod(i.Add(i, kB.D), c.Params().N)
 	return sskB
 }
 
-func GenMintProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) []byte {
-	value_c := C.ulong(ValueNew)     //转换后零知识余额对应的明文余额
-	value_old_c := C.ulong(ValueOld) //转换前零知识余额对应的明文余额
-
-	sn_old_c := C.CString(common.ToHex(SNold[:]))
-	r_old_c := C.CString(common.ToHex(RAold[:]))
-	sn_c := C.CString(common.ToHex(SNAnew[:]))
-	r_c := C.CString(common.ToHex(RAnew[:]))
-
-	cmtA_old_c := C.CString(common.ToHex(CMTold[:])) //对于CMT  需要将每一个byte拆为两个16进制字符
-	cmtA_c := C.CString(common.ToHex(CMTnew[:]))
-
-	value_s_c := C.ulong(ValueNew - ValueOld) //需要被转换的明文余额
-
-	sk_c := C.CString(common.ToHex(SK[:]))
-
-	cproof := C.genMintproof(value_c, value_old_c, sn_old_c, r_old_c, sn_c, r_c, cmtA_old_c, cmtA_c, value_s_c, sk_c)
-
-	var goproof string
-	goproof = C.GoString(cproof)
-	return []byte(goproof)
-}
-
-func GenSendProof(CMTA *common.Hash, ValueA uint64, RA *common.Hash, ValueS uint64, pk_recv *ecdsa.PublicKey, RS *common.Hash, SNA *common.Hash, CMTS *common.Hash, ValueAnew uint64, SNAnew *common.Hash, RAnew *common.Hash, CMTAnew *common.Hash, SK *common.Hash, pk_sender common.Address) []byte {
-	cmtA_c := C.CString(common.ToHex(CMTA[:]))
-	valueA_c := C.ulong(ValueA)
-	rA_c := C.CString(common.ToHex(RA.Bytes()[:]))
-	valueS := C.ulong(ValueS)
-	PK_recv := crypto.PubkeyToAddress(*pk_recv) //--zy
-	pk_recv_c := C.CString(common.ToHex(PK_recv[:]))
-	rS := C.CString(common.ToHex(RS.Bytes()[:]))
-	snA := C.CString(common.ToHex(SNA.Bytes()[:]))
-	cmtS := C.CString(common.ToHex(CMTS[:]))
-	//ValueAnew uint64 , SNAnew *common.Hash, RAnew *common.Hash,CMTAnew *common.Hash
-	valueANew_c := C.ulong(ValueAnew)
-	snAnew_c := C.CString(common.ToHex(SNAnew.Bytes()[:]))
-	rAnew_c := C.CString(common.ToHex(RAnew.Bytes()[:]))
-	cmtAnew_c := C.CString(common.ToHex(CMTAnew[:]))
-
-	sk_c := C.CString(common.ToHex(SK[:]))
-	//PK_sender := crypto.PubkeyToAddress(*pk_sender) //--zy
-	pk_sender_c := C.CString(common.ToHex(pk_sender[:]))
-
-	cproof := C.genSendproof(valueA_c, rS, snA, rA_c, cmtS, cmtA_c, valueS, pk_recv_c, valueANew_c, snAnew_c, rAnew_c, cmtAnew_c, sk_c, pk_sender_c)
-	var goproof string
-	goproof = C.GoString(cproof)
-	return []byte(goproof)
-}
-
-// func GenUpdateProof(CMTS *common.Hash, ValueS uint64, pk_recv *ecdsa.PublicKey, SNS *common.Hash, RS *common.Hash, SNA *common.Hash, ValueA uint64, RA *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTA *common.Hash, RTcmt []byte, CMTAnew *common.Hash, CMTSForMerkle []*common.Hash, n int) []byte {
-// 	cmtS_c := C.CString(common.ToHex(CMTS[:]))
-// 	valueS_c := C.ulong(ValueS)
-// 	PK_recv := crypto.PubkeyToAddress(*pk_recv) //--zy
-// 	pk_recv_c := C.CString(common.ToHex(PK_recv[:]))
-// 	SNS_c := C.CString(common.ToHex(SNS.Bytes()[:])) //--zy
-// 	RS_c := C.CString(common.ToHex(RS.Bytes()[:]))   //--zy
-// 	SNA_c := C.CString(common.ToHex(SNA.Bytes()[:]))
-// 	valueA_c := C.ulong(ValueA)
-// 	RA_c := C.CString(common.ToHex(RA.Bytes()[:])) //rA_c := C.CString(string(RA.Bytes()[:]))
-// 	SNAnew_c := C.CString(common.ToHex(SNAnew.Bytes()[:]))
-// 	RAnew_c := C.CString(common.ToHex(RAnew.Bytes()[:]))
-// 	cmtA_c := C.CString(common.ToHex(CMTA[:]))
-// 	RT_c := C.CString(common.ToHex(RTcmt)) //--zy   rt
-
-// 	cmtAnew_c := C.CString(common.ToHex(CMTAnew[:]))
-// 	valueANew_c := C.ulong(ValueA - ValueS)
-
-// 	var cmtArray string
-// 	for i := 0; i < len(CMTSForMerkle); i++ {
-// 		s := string(common.ToHex(CMTSForMerkle[i][:]))
-// 		cmtArray += s
-// 	}
-
-// 	cmtsM := C.CString(cmtArray)
-
-// 	nC := C.int(n)
-// 	cproof := C.genUpdateproof(valueANew_c, valueA_c, SNA_c, RA_c, SNAnew_c, RAnew_c, SNS_c, RS_c, cmtA_c, cmtAnew_c, valueS_c, pk_recv_c, cmtS_c, cmtsM, nC, RT_c)
-// 	var goproof string
-// 	goproof = C.GoString(cproof)
-// 	return []byte(goproof)
-// }
-
-func GenDepositProof(CMTS *common.Hash, ValueS uint64, SNS *common.Hash, RS *common.Hash, SNA *common.Hash, ValueB uint64, RB *common.Hash, SNBnew *common.Hash, RBnew *common.Hash, pk_recv *ecdsa.PublicKey, RTcmt []byte, CMTB *common.Hash, SNB *common.Hash, CMTBnew *common.Hash, CMTSForMerkle []*common.Hash, SK *common.Hash) []byte {
-	cmtS_c := C.CString(common.ToHex(CMTS[:]))
-	valueS_c := C.ulong(ValueS)
-	PK_recv := crypto.PubkeyToAddress(*pk_recv) //--zy
-	pk_recv_c := C.CString(common.ToHex(PK_recv[:]))
-	SNS_c := C.CString(common.ToHex(SNS.Bytes()[:])) //--zy
-	RS_c := C.CString(common.ToHex(RS.Bytes()[:]))   //--zy
-	SNA_c := C.CString(common.ToHex(SNA.Bytes()[:]))
-	valueB_c := C.ulong(ValueB)
-	RB_c := C.CString(common.ToHex(RB.Bytes()[:])) //rA_c := C.CString(string(RA.Bytes()[:]))
-	SNB_c := C.CString(common.ToHex(SNB.Bytes()[:]))
-	SNBnew_c := C.CString(common.ToHex(SNBnew.Bytes()[:]))
-	RBnew_c := C.CString(common.ToHex(RBnew.Bytes()[:]))
-	cmtB_c := C.CString(common.ToHex(CMTB[:]))
-	RT_c := C.CString(common.ToHex(RTcmt)) //--zy   rt
-
-	cmtBnew_c := C.CString(common.ToHex(CMTBnew[:]))
-	valueBNew_c := C.ulong(ValueB + ValueS)
-
-	SK_c := C.CString(common.ToHex(SK.Bytes()[:])) 
-
-	var cmtArray string
-	for i := 0; i < len(CMTSForMerkle); i++ {
-		s := string(common.ToHex(CMTSForMerkle[i][:]))
-		cmtArray += s
-	}
-	cmtsM := C.CString(cmtArray)
-	nC := C.int(len(CMTSForMerkle))
-
-	cproof := C.genDepositproof(valueBNew_c, valueB_c, SNB_c, RB_c, SNBnew_c, RBnew_c, SNS_c, RS_c, cmtB_c, cmtBnew_c, valueS_c, pk_recv_c, SNA_c, cmtS_c, cmtsM, nC, RT_c, SK_c)
-	var goproof string
-	goproof = C.GoString(cproof)
-	return []byte(goproof)
-}
-
-func GenRedeemProof(ValueOld uint64, RAold *common.Hash, SNAnew *common.Hash, RAnew *common.Hash, CMTold *common.Hash, SNold *common.Hash, CMTnew *common.Hash, ValueNew uint64, SK *common.Hash) []byte {
-	value_c := C.ulong(ValueNew)     //转换后零知识余额对应的明文余额
-	value_old_c := C.ulong(ValueOld) //转换前零知识余额对应的明文余额
-
-	sn_old_c := C.CString(common.ToHex(SNold.Bytes()[:]))
-	r_old_c := C.CString(common.ToHex(RAold.Bytes()[:]))
-	sn_c := C.CString(common.ToHex(SNAnew.Bytes()[:]))
-	r_c := C.CString(common.ToHex(RAnew.Bytes()[:]))
-
-	cmtA_old_c := C.CString(common.ToHex(CMTold[:])) //对于CMT  需要将每一个byte拆为两个16进制字符
-	cmtA_c := C.CString(common.ToHex(CMTnew[:]))
-
-	value_s_c := C.ulong(ValueOld - ValueNew) //需要被转换的明文余额
-
-	SK_c := C.CString(common.ToHex(SK.Bytes()[:])) 
-
-	cproof := C.genRedeemproof(value_c, value_old_c, sn_old_c, r_old_c, sn_c, r_c, cmtA_old_c, cmtA_c, value_s_c, SK_c)
-
-	var goproof string
-	goproof = C.GoString(cproof)
-	return []byte(goproof)
-}
-
 func GenR() *ecdsa.PrivateKey {
 	Ka, err := crypto.GenerateKey()
 	if err != nil {
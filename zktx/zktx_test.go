@@ -0,0 +1,67 @@
+package zktx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestAUX(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	rs, sna := common.BytesToHash([]byte("rs")), common.BytesToHash([]byte("sna"))
+	aux, err := ComputeAUX(&key.PublicKey, 5, &rs, &sna)
+	require.NoError(t, err)
+	require.Equal(t, byte(auxVersion), aux[0])
+
+	value, auxRS, auxSNA, err := DecAUX(key, aux)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), value)
+	require.Equal(t, rs, *auxRS)
+	require.Equal(t, sna, *auxSNA)
+
+	_, _, _, err = DecAUX(other, aux)
+	require.Error(t, err)
+
+	// the ciphertext and the version are authenticated
+	tampered := append([]byte{}, aux...)
+	tampered[len(tampered)-40] ^= 1
+	_, _, _, err = DecAUX(key, tampered)
+	require.Error(t, err)
+
+	tampered = append([]byte{}, aux...)
+	tampered[0] = auxVersion + 1
+	_, _, _, err = DecAUX(key, tampered)
+	require.Error(t, err)
+
+	_, _, _, err = DecAUX(key, nil)
+	require.Error(t, err)
+}
+
+func TestInitialNote(t *testing.T) {
+	initialNoteLock.Lock()
+	initialSN, initialCMT = nil, nil
+	initialNoteLock.Unlock()
+
+	_, _, err := InitialNote()
+	require.Equal(t, ErrInitialNoteUnknown, err)
+	_, err = InitializeSN()
+	require.Equal(t, ErrInitialNoteUnknown, err)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = NewShieldedKeys(key).SK(&Sequence{})
+	require.Equal(t, ErrInitialNoteUnknown, err)
+
+	sn, cmt := common.BytesToHash([]byte("sn")), common.BytesToHash([]byte("cmt"))
+	SetInitialNote(sn, cmt)
+	note, err := InitializeSN()
+	require.NoError(t, err)
+	require.Equal(t, sn, *note.SN)
+	require.Equal(t, cmt, *note.CMT)
+}